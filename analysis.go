@@ -1,15 +1,30 @@
 package main
 
 import (
+	"fmt"
 	"go/types"
-	"path/filepath"
+	"strings"
+)
+
+// Kinds of named types, as reported in Implementation.Kind and accepted by
+// the -kinds flag.
+const (
+	kindStruct    = "struct"
+	kindFunc      = "func"
+	kindSlice     = "slice"
+	kindArray     = "array"
+	kindMap       = "map"
+	kindBasic     = "basic"
+	kindPointer   = "pointer"
+	kindChan      = "chan"
+	kindInterface = "interface"
 )
 
 func (f *Finder) processTypeInScope(
 	obj types.Object, dirPath string, pkg *types.Package,
 ) {
 	typeName, ok := obj.(*types.TypeName)
-	if !ok {
+	if !ok || !f.allowsFile(f.fset.Position(typeName.Pos()).Filename) {
 		return
 	}
 
@@ -18,34 +33,148 @@ func (f *Finder) processTypeInScope(
 		return
 	}
 
-	if !f.isStructType(namedType) {
+	kind := typeKind(namedType)
+	if len(f.kinds) > 0 && !f.kinds[kind] {
+		return
+	}
+
+	// The target trivially satisfies itself.
+	if f.target != nil && namedType.Origin() == f.target.Origin() {
+		return
+	}
+
+	match, ok := f.matchType(namedType)
+	if !ok || (f.valueOnly && !match.valueImplements) {
+		f.processNearMiss(namedType, dirPath, pkg, typeName, match)
+
+		return
+	}
+
+	impl := f.createImplementation(dirPath, pkg, typeName)
+	impl.ValueImplements = match.valueImplements
+	impl.PointerImplements = match.pointerImplements
+	impl.TypeArgs = typeStrings(match.typeArgs)
+	impl.InterfaceTypeArgs = typeStrings(match.interfaceTypeArgs)
+	impl.Promoted = f.promotedMethods(namedType)
+	f.results = append(f.results, impl)
+
+	if f.hierarchy {
+		f.recordHierarchyType(impl, namedType)
+	}
+}
+
+// promotedMethods lists the interface methods namedType gets through
+// embedded fields, which is how wrapper and decorator types implement an
+// interface, often with the embedded type coming from another package.
+func (f *Finder) promotedMethods(namedType *types.Named) []PromotedMethod {
+	var promoted []PromotedMethod
+
+	for _, method := range f.explainMethods(namedType) {
+		if method.Depth == 0 || method.Status != statusOK {
+			continue
+		}
+
+		promoted = append(promoted, PromotedMethod{
+			Method: method.Method,
+			Via:    method.Via,
+			From:   method.From,
+		})
+	}
+
+	return promoted
+}
+
+// processNearMiss reports a type that failed to match when -near-miss is on
+// and the type is close enough.
+func (f *Finder) processNearMiss(
+	namedType *types.Named,
+	dirPath string,
+	pkg *types.Package,
+	typeName *types.TypeName,
+	match typeMatch,
+) {
+	if f.nearMiss <= 0 || f.iface == nil || f.iface.Empty() {
 		return
 	}
 
-	if !f.typeImplementsInterface(namedType) {
+	missing, mismatched := f.nearMissProblems(namedType)
+	if !f.isNearMiss(missing, mismatched) {
 		return
 	}
 
 	impl := f.createImplementation(dirPath, pkg, typeName)
+	impl.ValueImplements = match.valueImplements
+	impl.PointerImplements = match.pointerImplements
+	impl.Missing = missing
+	impl.Mismatched = mismatched
 	f.results = append(f.results, impl)
 }
 
-func (f *Finder) isStructType(namedType *types.Named) bool {
-	_, ok := namedType.Underlying().(*types.Struct)
+// typeKind classifies a named type by its underlying type.
+func typeKind(namedType *types.Named) string {
+	switch namedType.Underlying().(type) {
+	case *types.Struct:
+		return kindStruct
+	case *types.Signature:
+		return kindFunc
+	case *types.Slice:
+		return kindSlice
+	case *types.Array:
+		return kindArray
+	case *types.Map:
+		return kindMap
+	case *types.Pointer:
+		return kindPointer
+	case *types.Chan:
+		return kindChan
+	case *types.Interface:
+		return kindInterface
+	default:
+		return kindBasic
+	}
+}
+
+// parseKinds turns a comma-separated -kinds value into a set. An empty value
+// yields an empty set, which places no restriction.
+func parseKinds(value string) (map[string]bool, error) {
+	kinds := make(map[string]bool)
+
+	if strings.TrimSpace(value) == "" {
+		return kinds, nil
+	}
+
+	for kind := range strings.SplitSeq(value, ",") {
+		kind = strings.TrimSpace(kind)
 
-	return ok
+		switch kind {
+		case kindStruct, kindFunc, kindSlice, kindArray, kindMap,
+			kindBasic, kindPointer, kindChan, kindInterface:
+			kinds[kind] = true
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+		}
+	}
+
+	return kinds, nil
 }
 
 func (f *Finder) createImplementation(
 	dirPath string, pkg *types.Package, typeName *types.TypeName,
 ) Implementation {
-	relPath, _ := filepath.Rel(".", dirPath)
-	packagePath := filepath.Join(f.modulePath, relPath)
-	packagePath = filepath.ToSlash(packagePath)
-
-	return Implementation{
+	impl := Implementation{
 		Package:     pkg.Name(),
 		Struct:      typeName.Name(),
-		PackagePath: packagePath,
+		PackagePath: f.importPathForDir(dirPath),
+		Module:      f.modulePathForDir(dirPath),
 	}
+
+	if dep, ok := f.dependencyForDir(dirPath); ok {
+		impl.Version = dep.Version
+	}
+
+	if namedType, ok := typeName.Type().(*types.Named); ok {
+		impl.Kind = typeKind(namedType)
+	}
+
+	return impl
 }
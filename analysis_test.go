@@ -5,18 +5,17 @@ import (
 	"go/parser"
 	"go/token"
 	"go/types"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestIsStructType(t *testing.T) {
+func TestTypeKind(t *testing.T) {
 	t.Parallel()
 
-	finder := NewFinder("TestInterface")
-
-	src := `
+	pkg := checkSource(t, "test", `
 package test
 
 type TestStruct struct {
@@ -27,34 +26,122 @@ type TestInterface interface {
 	Method()
 }
 
+type HandlerFunc func(string) error
+type IDs []string
+type Digest [32]byte
+type Labels map[string]string
+type Level int
+type Name string
+type NodePtr *TestStruct
+type Events chan string
+
 type TestAlias = string
-`
+`)
 
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "test.go", src, 0)
-	require.NoError(t, err)
+	testCases := []struct {
+		typeName string
+		expected string
+	}{
+		{typeName: "TestStruct", expected: "struct"},
+		{typeName: "TestInterface", expected: "interface"},
+		{typeName: "HandlerFunc", expected: "func"},
+		{typeName: "IDs", expected: "slice"},
+		{typeName: "Digest", expected: "array"},
+		{typeName: "Labels", expected: "map"},
+		{typeName: "Level", expected: "basic"},
+		{typeName: "Name", expected: "basic"},
+		{typeName: "NodePtr", expected: "pointer"},
+		{typeName: "Events", expected: "chan"},
+	}
 
-	config := &types.Config{
-		Error: func(err error) {},
+	for _, tc := range testCases {
+		t.Run(tc.typeName, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, typeKind(lookupNamed(t, pkg, tc.typeName)))
+		})
 	}
+}
+
+func TestParseKinds(t *testing.T) {
+	t.Parallel()
+
+	kinds, err := parseKinds("")
+	require.NoError(t, err)
+	assert.Empty(t, kinds)
 
-	pkg, err := config.Check("test", fset, []*ast.File{file}, nil)
+	kinds, err = parseKinds("struct, func,map")
 	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"struct": true, "func": true, "map": true}, kinds)
+
+	_, err = parseKinds("struct,class")
+	require.ErrorIs(t, err, ErrUnknownKind)
+}
+
+func TestProcessTypeInScopeKinds(t *testing.T) {
+	t.Parallel()
+
+	pkg := checkSource(t, "testpkg", `
+package testpkg
+
+type Stringer interface {
+	String() string
+}
+
+type Describer interface {
+	Stringer
+	Describe() string
+}
+
+type HandlerFunc func() string
+
+func (h HandlerFunc) String() string { return h() }
+
+type IDs []string
+
+func (ids IDs) String() string { return "" }
+
+type Level int
+
+func (l *Level) String() string { return "" }
+
+type Labels map[string]string
+
+func (l Labels) String() string { return "" }
+
+type Events chan string
+
+func (e Events) String() string { return "" }
+
+type Server struct{}
+
+func (s *Server) String() string { return "" }
+
+type Silent struct{}
+`)
 
 	testCases := []struct {
-		name       string
-		typeName   string
-		expectTrue bool
+		name     string
+		kinds    string
+		expected map[string]string
 	}{
 		{
-			name:       "struct type",
-			typeName:   "TestStruct",
-			expectTrue: true,
+			name:  "all kinds",
+			kinds: "",
+			expected: map[string]string{
+				"Describer":   "interface",
+				"HandlerFunc": "func",
+				"IDs":         "slice",
+				"Level":       "basic",
+				"Labels":      "map",
+				"Events":      "chan",
+				"Server":      "struct",
+			},
 		},
 		{
-			name:       "interface type",
-			typeName:   "TestInterface",
-			expectTrue: false,
+			name:     "restricted kinds",
+			kinds:    "struct,func",
+			expected: map[string]string{"HandlerFunc": "func", "Server": "struct"},
 		},
 	}
 
@@ -62,17 +149,111 @@ type TestAlias = string
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			obj := pkg.Scope().Lookup(tc.typeName)
-			require.NotNil(t, obj, "type %s not found", tc.typeName)
+			kinds, err := parseKinds(tc.kinds)
+			require.NoError(t, err)
 
-			typeName, ok := obj.(*types.TypeName)
-			require.True(t, ok, "%s is not a type name", tc.typeName)
+			finder := NewFinder("Stringer")
+			finder.kinds = kinds
+			require.NoError(t, finder.setTarget(lookupNamed(t, pkg, "Stringer")))
 
-			namedType, ok := typeName.Type().(*types.Named)
-			require.True(t, ok, "%s is not a named type", tc.typeName)
+			finder.findImplementationsInTypedPackage("./testpkg", pkg)
 
-			result := finder.isStructType(namedType)
-			assert.Equal(t, tc.expectTrue, result)
+			found := make(map[string]string)
+			for _, result := range finder.getResults() {
+				found[result.Struct] = result.Kind
+			}
+
+			assert.Equal(t, tc.expected, found)
+		})
+	}
+}
+
+func TestProcessTypeInScopeReceivers(t *testing.T) {
+	t.Parallel()
+
+	pkg := checkSource(t, "testpkg", `
+package testpkg
+
+type Server interface {
+	Start() error
+	Stop() error
+}
+
+type ValueServer struct{}
+
+func (ValueServer) Start() error { return nil }
+func (ValueServer) Stop() error  { return nil }
+
+type PointerServer struct{}
+
+func (*PointerServer) Start() error { return nil }
+func (*PointerServer) Stop() error  { return nil }
+
+type MixedServer struct{}
+
+func (MixedServer) Start() error { return nil }
+func (*MixedServer) Stop() error { return nil }
+
+type Controller interface {
+	Server
+	Restart() error
+}
+
+type genericServer[T any] struct{}
+
+func (*genericServer[T]) Start() error { return nil }
+func (*genericServer[T]) Stop() error  { return nil }
+`)
+
+	type receivers struct {
+		value   bool
+		pointer bool
+	}
+
+	testCases := []struct {
+		name      string
+		valueOnly bool
+		expected  map[string]receivers
+	}{
+		{
+			name: "all receivers",
+			expected: map[string]receivers{
+				"ValueServer":   {value: true, pointer: true},
+				"PointerServer": {value: false, pointer: true},
+				"MixedServer":   {value: false, pointer: true},
+				"Controller":    {value: true, pointer: false},
+				"genericServer": {value: false, pointer: true},
+			},
+		},
+		{
+			name:      "value only",
+			valueOnly: true,
+			expected: map[string]receivers{
+				"ValueServer": {value: true, pointer: true},
+				"Controller":  {value: true, pointer: false},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := NewFinder("Server")
+			finder.valueOnly = tc.valueOnly
+			require.NoError(t, finder.setTarget(lookupNamed(t, pkg, "Server")))
+
+			finder.findImplementationsInTypedPackage("./testpkg", pkg)
+
+			found := make(map[string]receivers)
+			for _, result := range finder.getResults() {
+				found[result.Struct] = receivers{
+					value:   result.ValueImplements,
+					pointer: result.PointerImplements,
+				}
+			}
+
+			assert.Equal(t, tc.expected, found)
 		})
 	}
 }
@@ -107,6 +288,7 @@ type TestStruct struct{}
 
 	assert.Equal(t, "testpkg", impl.Package)
 	assert.Equal(t, "TestStruct", impl.Struct)
+	assert.Equal(t, "struct", impl.Kind)
 
 	expectedPath := "github.com/test/repo/pkg/testpkg"
 	assert.Equal(t, expectedPath, impl.PackagePath)
@@ -116,12 +298,16 @@ func TestProcessTypeInScope(t *testing.T) {
 	t.Parallel()
 
 	finder := NewFinder("TestInterface")
-	finder.interfaceMethods = []string{"Start", "Stop"}
 	finder.modulePath = "github.com/test/repo"
 
 	src := `
 package testpkg
 
+type Server interface {
+	Start() error
+	Stop() error
+}
+
 type TestStruct struct{}
 
 func (t *TestStruct) Start() error { return nil }
@@ -148,6 +334,8 @@ type NotAStruct interface {
 	pkg, err := config.Check("testpkg", fset, []*ast.File{file}, nil)
 	require.NoError(t, err)
 
+	finder.iface = lookupInterface(t, pkg, "Server")
+
 	// Test with complete implementation
 	obj := pkg.Scope().Lookup("TestStruct")
 	finder.processTypeInScope(obj, "./pkg/testpkg", pkg)
@@ -172,11 +360,14 @@ type NotAStruct interface {
 func TestProcessTypeInScopeEdgeCases(t *testing.T) {
 	// not parallel: subtests share and mutate finder.results, a race under t.Parallel()
 	finder := NewFinder("TestInterface")
-	finder.interfaceMethods = []string{"Method"}
 
 	src := `
 package testpkg
 
+type TestInterface interface {
+	Method()
+}
+
 var GlobalVar int = 42
 const GlobalConst = "test"
 
@@ -194,6 +385,8 @@ func GlobalFunc() {}
 	pkg, err := config.Check("testpkg", fset, []*ast.File{file}, nil)
 	require.NoError(t, err)
 
+	finder.iface = lookupInterface(t, pkg, "TestInterface")
+
 	// Test with non-TypeName objects (variable, constant, function)
 	testCases := []struct {
 		name     string
@@ -230,3 +423,68 @@ func GlobalFunc() {}
 		})
 	}
 }
+
+func TestFinder_PromotedMethods(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Service interface {
+	Start() error
+	Stop() error
+	Name() string
+}
+`,
+		"base/server.go": `package base
+
+type Server struct{}
+
+func (s *Server) Start() error { return nil }
+func (s *Server) Stop() error  { return nil }
+`,
+		"wrap/wrap.go": `package wrap
+
+import "example.com/app/base"
+
+type Logged struct {
+	*base.Server
+}
+
+func (Logged) Name() string { return "logged" }
+
+type Traced struct {
+	Logged
+}
+
+func (Traced) Stop() error { return nil }
+
+type Plain struct{}
+
+func (Plain) Start() error  { return nil }
+func (Plain) Stop() error   { return nil }
+func (Plain) Name() string  { return "" }
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Service")
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Service"}))
+	require.NoError(t, finder.scanDirectory(filepath.Join(root, "wrap")))
+
+	promoted := make(map[string][]PromotedMethod)
+	for _, result := range finder.getResults() {
+		promoted[result.Struct] = result.Promoted
+	}
+
+	assert.Equal(t, map[string][]PromotedMethod{
+		"Logged": {
+			{Method: "Start", Via: []string{"Server"}, From: "example.com/app/base.Server"},
+			{Method: "Stop", Via: []string{"Server"}, From: "example.com/app/base.Server"},
+		},
+		"Traced": {
+			{Method: "Name", Via: []string{"Logged"}, From: "example.com/app/wrap.Logged"},
+			{Method: "Start", Via: []string{"Logged", "Server"}, From: "example.com/app/base.Server"},
+		},
+		"Plain": nil,
+	}, promoted)
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Assertion is a compile-time assertion such as
+// var _ Server = (*WebServer)(nil). Position is file:line relative to the
+// module root and Pointer tells whether it asserts the pointer type. Valid is
+// false when the type no longer implements the interface, which go build
+// reports as an error.
+type Assertion struct {
+	Position string `json:"position"`
+	Pointer  bool   `json:"pointer"`
+	Valid    bool   `json:"valid"`
+}
+
+// assertedType is an assertion site along with the type it asserts, pointer
+// stripped.
+type assertedType struct {
+	named     *types.Named
+	assertion Assertion
+}
+
+// enableAssertions makes type-checking record what collectAssertions needs.
+func (f *Finder) enableAssertions() {
+	f.info = &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	f.checkedFiles = make(map[*types.Package][]*ast.File)
+	f.assertions = make(map[string][]assertedType)
+	f.assertionSeen = make(map[string]bool)
+	f.scanned = make(map[*types.Package]bool)
+}
+
+// collectAssertions records the package-level var _ Target = value
+// declarations in pkg's files. Files checked again as part of a test variant
+// are only counted once.
+func (f *Finder) collectAssertions(pkg *types.Package) {
+	if f.info == nil || f.target == nil {
+		return
+	}
+
+	f.scanned[pkg] = true
+
+	for _, file := range f.checkedFiles[pkg] {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+					f.collectValueSpecAssertions(valueSpec)
+				}
+			}
+		}
+	}
+}
+
+func (f *Finder) collectValueSpecAssertions(spec *ast.ValueSpec) {
+	if spec.Type == nil || len(spec.Names) != len(spec.Values) {
+		return
+	}
+
+	declared := f.info.TypeOf(spec.Type)
+
+	declaredNamed, ok := types.Unalias(declared).(*types.Named)
+	if !ok || declaredNamed.Origin() != f.target.Origin() {
+		return
+	}
+
+	for i, name := range spec.Names {
+		if name.Name != "_" {
+			continue
+		}
+
+		value := f.info.TypeOf(spec.Values[i])
+
+		named, ok := types.Unalias(derefType(value)).(*types.Named)
+		if !ok {
+			continue
+		}
+
+		position := f.positionString(spec.Values[i].Pos())
+		if f.assertionSeen[position] {
+			continue
+		}
+
+		f.assertionSeen[position] = true
+
+		key := assertionKey(named)
+		f.assertions[key] = append(f.assertions[key], assertedType{
+			named: named,
+			assertion: Assertion{
+				Position: position,
+				Pointer:  isPointer(value),
+				Valid:    types.AssignableTo(value, declared),
+			},
+		})
+	}
+}
+
+// assertionKey identifies a type the way results do, by package path and
+// name; types of an external test package count as their package's.
+func assertionKey(named *types.Named) string {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+
+	return strings.TrimSuffix(obj.Pkg().Path(), externalTestSuffix) + "." + obj.Name()
+}
+
+// positionString formats pos as file:line relative to the module root, or
+// to the directory of go.work in a workspace.
+func (f *Finder) positionString(pos token.Pos) string {
+	position := f.fset.Position(pos)
+	filename := position.Filename
+
+	base := f.moduleRoot
+	if f.workFile != "" {
+		base = filepath.Dir(f.workFile)
+	}
+
+	root, rootErr := filepath.Abs(base)
+	abs, absErr := filepath.Abs(filename)
+
+	if rootErr == nil && absErr == nil {
+		if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = filepath.ToSlash(rel)
+		}
+	}
+
+	return fmt.Sprintf("%s:%d", filename, position.Line)
+}
+
+// applyAssertions attaches the collected assertions to the results. A type
+// asserted without implementing the interface is added to the results, with
+// what it misses, so the stale assertion gets reported.
+func (f *Finder) applyAssertions() {
+	if f.info == nil {
+		return
+	}
+
+	index := make(map[string]int, len(f.results))
+	for i, impl := range f.results {
+		index[impl.PackagePath+"."+impl.Struct] = i
+	}
+
+	keys := make([]string, 0, len(f.assertions))
+	for key := range f.assertions {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		sites := f.assertions[key]
+
+		pos, ok := index[key]
+		if !ok {
+			if !slices.ContainsFunc(sites, func(site assertedType) bool {
+				return !site.assertion.Valid
+			}) {
+				continue
+			}
+
+			pos = len(f.results)
+			f.results = append(f.results, f.staleImplementation(sites[0]))
+		}
+
+		for _, site := range sites {
+			f.results[pos].Assertions = append(f.results[pos].Assertions, site.assertion)
+		}
+	}
+}
+
+// staleImplementation reports a type that is asserted to implement the
+// interface but does not.
+func (f *Finder) staleImplementation(site assertedType) Implementation {
+	obj := site.named.Obj()
+	missing, mismatched := f.nearMissProblems(site.named)
+
+	impl := Implementation{
+		Package:     obj.Pkg().Name(),
+		Struct:      obj.Name(),
+		Kind:        typeKind(site.named),
+		PackagePath: strings.TrimSuffix(obj.Pkg().Path(), externalTestSuffix),
+		Missing:     missing,
+		Mismatched:  mismatched,
+	}
+
+	if module := f.moduleForImport(impl.PackagePath); module != nil {
+		impl.Module = module.Path
+	}
+
+	if strings.HasSuffix(f.fset.Position(obj.Pos()).Filename, "_test.go") {
+		impl.InTest = true
+		impl.TestPackage = obj.Pkg().Name()
+	}
+
+	return impl
+}
+
+// checkAssertions fails when an implementation has no valid assertion or an
+// assertion has gone stale, for -require-assertions.
+func checkAssertions(implementations []Implementation) error {
+	var missing, stale []string
+
+	for _, impl := range implementations {
+		asserted := false
+
+		for _, assertion := range impl.Assertions {
+			if assertion.Valid {
+				asserted = true
+			} else {
+				stale = append(stale, assertion.Position)
+			}
+		}
+
+		if !asserted && (impl.ValueImplements || impl.PointerImplements) {
+			missing = append(missing, impl.PackagePath+"."+impl.Struct)
+		}
+	}
+
+	var errs []error
+
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrMissingAssertions, strings.Join(missing, ", ")))
+	}
+
+	if len(stale) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrStaleAssertions, strings.Join(stale, ", ")))
+	}
+
+	return errors.Join(errs...)
+}
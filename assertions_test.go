@@ -0,0 +1,202 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertionsModule(t *testing.T) string {
+	t.Helper()
+
+	return newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Server interface {
+	Start() error
+	Stop() error
+}
+`,
+		"impl/impl.go": `package impl
+
+import "example.com/app/app"
+
+type Web struct{}
+
+func (*Web) Start() error { return nil }
+func (*Web) Stop() error  { return nil }
+
+var _ app.Server = (*Web)(nil)
+
+type Plain struct{}
+
+func (Plain) Start() error { return nil }
+func (Plain) Stop() error  { return nil }
+
+type Old struct{}
+
+func (Old) Start() error { return nil }
+
+var (
+	_ app.Server = Old{}
+	_ app.Server = Web{}
+)
+
+var _ interface{ Start() error } = Old{}
+`,
+		"impl/impl_test.go": `package impl
+
+import "example.com/app/app"
+
+var _ app.Server = Plain{}
+`,
+	})
+}
+
+func TestFinder_Assertions(t *testing.T) {
+	t.Parallel()
+
+	root := assertionsModule(t)
+
+	type found struct {
+		implements bool
+		assertions []Assertion
+	}
+
+	testCases := []struct {
+		name       string
+		assertions bool
+		tests      bool
+		expected   map[string]found
+	}{
+		{
+			name: "disabled",
+			expected: map[string]found{
+				"Web":   {implements: true},
+				"Plain": {implements: true},
+			},
+		},
+		{
+			name:       "enabled",
+			assertions: true,
+			expected: map[string]found{
+				"Web": {implements: true, assertions: []Assertion{
+					{Position: "impl/impl.go:10", Pointer: true, Valid: true},
+					{Position: "impl/impl.go:23", Valid: false},
+				}},
+				"Plain": {implements: true},
+				"Old": {assertions: []Assertion{
+					{Position: "impl/impl.go:22", Valid: false},
+				}},
+			},
+		},
+		{
+			name:       "enabled with tests",
+			assertions: true,
+			tests:      true,
+			expected: map[string]found{
+				"Web": {implements: true, assertions: []Assertion{
+					{Position: "impl/impl.go:10", Pointer: true, Valid: true},
+					{Position: "impl/impl.go:23", Valid: false},
+				}},
+				"Plain": {implements: true, assertions: []Assertion{
+					{Position: "impl/impl_test.go:5", Valid: true},
+				}},
+				"Old": {assertions: []Assertion{
+					{Position: "impl/impl.go:22", Valid: false},
+				}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, "Server")
+			finder.tests = tc.tests
+
+			if tc.assertions {
+				finder.enableAssertions()
+			}
+
+			require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Server"}))
+			require.NoError(t, finder.scanDirectory(root))
+
+			results := make(map[string]found)
+			for _, result := range finder.getResults() {
+				results[result.Struct] = found{
+					implements: result.ValueImplements || result.PointerImplements,
+					assertions: result.Assertions,
+				}
+			}
+
+			assert.Equal(t, tc.expected, results)
+		})
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	t.Parallel()
+
+	valid := Assertion{Position: "impl/impl.go:10", Pointer: true, Valid: true}
+	stale := Assertion{Position: "impl/impl.go:12", Valid: false}
+
+	testCases := []struct {
+		name            string
+		implementations []Implementation
+		expectedErrs    []error
+	}{
+		{
+			name: "all asserted",
+			implementations: []Implementation{
+				{Struct: "Web", PointerImplements: true, Assertions: []Assertion{valid}},
+			},
+		},
+		{
+			name: "near miss needs no assertion",
+			implementations: []Implementation{
+				{Struct: "Old", Missing: []MethodProblem{{Method: "Stop"}}},
+			},
+		},
+		{
+			name: "missing assertion",
+			implementations: []Implementation{
+				{Struct: "Plain", ValueImplements: true, PointerImplements: true},
+			},
+			expectedErrs: []error{ErrMissingAssertions},
+		},
+		{
+			name: "stale assertion",
+			implementations: []Implementation{
+				{Struct: "Web", PointerImplements: true, Assertions: []Assertion{valid, stale}},
+			},
+			expectedErrs: []error{ErrStaleAssertions},
+		},
+		{
+			name: "only stale assertions",
+			implementations: []Implementation{
+				{Struct: "Web", PointerImplements: true, Assertions: []Assertion{stale}},
+			},
+			expectedErrs: []error{ErrMissingAssertions, ErrStaleAssertions},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkAssertions(tc.implementations)
+			if len(tc.expectedErrs) == 0 {
+				require.NoError(t, err)
+
+				return
+			}
+
+			for _, expectedErr := range tc.expectedErrs {
+				require.ErrorIs(t, err, expectedErr)
+			}
+		})
+	}
+}
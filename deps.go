@@ -0,0 +1,306 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// dependencyPackage is a package of a dependency module that -deps scans.
+// Version is empty for a module replaced by a local directory.
+type dependencyPackage struct {
+	ImportPath string
+	Dir        string
+	Module     string
+	Version    string
+}
+
+// vendoredModule is a module as vendor/modules.txt lists it, along with the
+// packages of it that are vendored.
+type vendoredModule struct {
+	Path     string
+	Version  string
+	Packages []string
+}
+
+// stdModule is the module the standard library's packages belong to, as
+// GOROOT/src/go.mod names it.
+const stdModule = "std"
+
+// scanDependencies scans the packages of the module's dependencies, offline:
+// from vendor/ when the module is vendored, otherwise from the module cache
+// and local replacements. Modules that are not there are skipped.
+func (f *Finder) scanDependencies() {
+	f.scanDependencyPackages(f.dependencyPackages())
+}
+
+// scanStd scans the standard library in GOROOT/src.
+func (f *Finder) scanStd() {
+	f.scanDependencyPackages(f.stdPackages())
+}
+
+// scanDependencyPackages scans packages from outside the scanned modules,
+// recording where they come from for the results.
+func (f *Finder) scanDependencyPackages(packages []dependencyPackage) {
+	if f.depPackages == nil {
+		f.depPackages = make(map[string]dependencyPackage)
+	}
+
+	for _, dep := range packages {
+		absDir, err := filepath.Abs(dep.Dir)
+		if err != nil {
+			continue
+		}
+
+		f.depPackages[absDir] = dep
+
+		pkg, err := f.loadPackage(dep.ImportPath, dep.Dir)
+		if err != nil {
+			slog.Debug("failed to load dependency package",
+				"package", dep.ImportPath, "err", err)
+
+			continue
+		}
+
+		f.findImplementationsInTypedPackage(dep.Dir, pkg)
+	}
+}
+
+// dependencyPackages lists the dependency packages -deps scans: those
+// vendor/modules.txt lists when there is one, otherwise every package of
+// every module the scanned modules require.
+func (f *Finder) dependencyPackages() []dependencyPackage {
+	vendorRoot := f.vendorRoot()
+
+	content, err := os.ReadFile(filepath.Join(vendorRoot, "modules.txt"))
+	if err == nil {
+		return vendoredPackages(vendorRoot, parseVendorModules(string(content)))
+	}
+
+	var (
+		packages []dependencyPackage
+		seen     = make(map[string]bool)
+	)
+
+	for _, module := range f.workspaceModules() {
+		seen[module.Path] = true
+	}
+
+	for _, module := range f.workspaceModules() {
+		if module.GoMod == nil {
+			continue
+		}
+
+		requirements := make([]string, 0, len(module.GoMod.Requires))
+		for requirement := range module.GoMod.Requires {
+			requirements = append(requirements, requirement)
+		}
+
+		slices.Sort(requirements)
+
+		for _, modPath := range requirements {
+			if seen[modPath] {
+				continue
+			}
+
+			seen[modPath] = true
+
+			root, version := f.requirementRoot(modPath, module)
+			if !isDir(root) {
+				slog.Debug("skipping dependency missing from the module cache",
+					"module", modPath, "dir", root)
+
+				continue
+			}
+
+			packages = append(packages, modulePackages(modPath, version, root)...)
+		}
+	}
+
+	return packages
+}
+
+func vendoredPackages(vendorRoot string, modules []vendoredModule) []dependencyPackage {
+	var packages []dependencyPackage
+
+	for _, module := range modules {
+		for _, importPath := range module.Packages {
+			packages = append(packages, dependencyPackage{
+				ImportPath: importPath,
+				Dir:        filepath.Join(vendorRoot, filepath.FromSlash(importPath)),
+				Module:     module.Path,
+				Version:    module.Version,
+			})
+		}
+	}
+
+	return packages
+}
+
+// modulePackages lists the packages of the module rooted at root.
+func modulePackages(modPath, version, root string) []dependencyPackage {
+	var packages []dependencyPackage
+
+	for _, rel := range packageDirs(root, nil) {
+		importPath := modPath
+		if rel != "." {
+			importPath += "/" + rel
+		}
+
+		packages = append(packages, dependencyPackage{
+			ImportPath: importPath,
+			Dir:        filepath.Join(root, filepath.FromSlash(rel)),
+			Module:     modPath,
+			Version:    version,
+		})
+	}
+
+	return packages
+}
+
+// stdPackages lists the standard library packages code outside GOROOT can
+// import: everything in GOROOT/src but the cmd tree, internal and vendored
+// packages, and the builtin and unsafe pseudo-packages.
+func (f *Finder) stdPackages() []dependencyPackage {
+	root := filepath.Join(f.goroot, "src")
+	skip := func(rel string) bool {
+		return rel == "cmd" || rel == "builtin" || rel == "unsafe" ||
+			slices.Contains(strings.Split(rel, "/"), "internal")
+	}
+
+	var packages []dependencyPackage
+
+	for _, rel := range packageDirs(root, skip) {
+		if rel == "." {
+			continue
+		}
+
+		packages = append(packages, dependencyPackage{
+			ImportPath: rel,
+			Dir:        filepath.Join(root, filepath.FromSlash(rel)),
+			Module:     stdModule,
+		})
+	}
+
+	return packages
+}
+
+// packageDirs lists, relative to root and slash-separated, the directories
+// below root holding non-test Go files. Like the go command does for
+// root/..., it leaves out testdata, "." and "_" directories, vendor/ and
+// nested modules, as well as the directories skip rejects.
+func packageDirs(root string, skip func(rel string) bool) []string {
+	var dirs []string
+
+	_ = filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			slog.Debug("skipping unreadable dependency directory", "dir", path, "err", err)
+
+			return filepath.SkipDir
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+
+		if path != root {
+			if isIgnoredDir(entry.Name()) || entry.Name() == "vendor" ||
+				isFile(filepath.Join(path, "go.mod")) || skip != nil && skip(rel) {
+				return filepath.SkipDir
+			}
+		}
+
+		if hasGoFiles(path) {
+			dirs = append(dirs, rel)
+		}
+
+		return nil
+	})
+
+	return dirs
+}
+
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(entries, func(entry os.DirEntry) bool {
+		return !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") &&
+			!strings.HasSuffix(entry.Name(), "_test.go")
+	})
+}
+
+// parseVendorModules reads vendor/modules.txt: a "# path version" line per
+// module, possibly followed by "=> replacement", then the module's vendored
+// packages one per line. "##" lines carry annotations and are skipped.
+func parseVendorModules(content string) []vendoredModule {
+	var modules []vendoredModule
+
+	for line := range strings.SplitSeq(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "##"):
+			continue
+		case strings.HasPrefix(line, "# "):
+			modules = append(modules, parseVendorModuleLine(line))
+		case len(modules) > 0:
+			last := &modules[len(modules)-1]
+			last.Packages = append(last.Packages, line)
+		}
+	}
+
+	return modules
+}
+
+// parseVendorModuleLine parses "# path version [=> replacement [version]]".
+// The version is the one vendored: the replacement's if it has one.
+func parseVendorModuleLine(line string) vendoredModule {
+	module, replacement, replaced := strings.Cut(strings.TrimPrefix(line, "# "), "=>")
+
+	fields := strings.Fields(module)
+	if len(fields) == 0 {
+		return vendoredModule{}
+	}
+
+	vendored := vendoredModule{Path: fields[0]}
+	if len(fields) > 1 {
+		vendored.Version = fields[1]
+	}
+
+	if replaced {
+		replacementFields := strings.Fields(replacement)
+
+		switch {
+		case len(replacementFields) > 1:
+			vendored.Version = replacementFields[1]
+		case len(replacementFields) == 1 && isLocalReplacement(replacementFields[0]):
+			vendored.Version = ""
+		}
+	}
+
+	return vendored
+}
+
+// dependencyForDir returns the dependency package -deps scanned from dir.
+func (f *Finder) dependencyForDir(dir string) (dependencyPackage, bool) {
+	if len(f.depPackages) == 0 {
+		return dependencyPackage{}, false
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return dependencyPackage{}, false
+	}
+
+	dep, ok := f.depPackages[absDir]
+
+	return dep, ok
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVendorModules(t *testing.T) {
+	t.Parallel()
+
+	content := `# github.com/aws/sdk v1.2.3
+## explicit; go 1.21
+github.com/aws/sdk/s3
+github.com/aws/sdk/s3/types
+# example.com/forked v1.0.0 => example.com/fork v1.0.1
+## explicit
+example.com/forked
+# example.com/local v0.1.0 => ../local
+example.com/local/pkg
+`
+
+	assert.Equal(t, []vendoredModule{
+		{
+			Path:     "github.com/aws/sdk",
+			Version:  "v1.2.3",
+			Packages: []string{"github.com/aws/sdk/s3", "github.com/aws/sdk/s3/types"},
+		},
+		{Path: "example.com/forked", Version: "v1.0.1", Packages: []string{"example.com/forked"}},
+		{Path: "example.com/local", Packages: []string{"example.com/local/pkg"}},
+	}, parseVendorModules(content))
+}
+
+func TestFinder_ScanDependencies(t *testing.T) {
+	t.Parallel()
+
+	store := func(pkg, name string) string {
+		return "package " + pkg + "\n\ntype " + name + ` struct{}
+
+func (*` + name + `) Put(key string, data []byte) error { return nil }
+`
+	}
+
+	app := map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.24
+
+require (
+	example.com/blob v1.2.0
+	example.com/local v0.0.0
+	example.com/missing v1.0.0
+)
+
+replace example.com/local => ../local
+`,
+		"app/app.go": `package app
+
+type BlobStore interface {
+	Put(key string, data []byte) error
+}
+`,
+		"impl/impl.go": store("impl", "Mem"),
+	}
+
+	testCases := []struct {
+		name   string
+		vendor bool
+		want   []Implementation
+	}{
+		{
+			name: "module cache and local replacements",
+			want: []Implementation{
+				{Struct: "Client", PackagePath: "example.com/blob/s3", Module: "example.com/blob", Version: "v1.2.0"},
+				{Struct: "Disk", PackagePath: "example.com/local/disk", Module: "example.com/local"},
+				{Struct: "Mem", PackagePath: "example.com/app/impl", Module: "example.com/app"},
+			},
+		},
+		{
+			name:   "vendor",
+			vendor: true,
+			want: []Implementation{
+				{Struct: "Mem", PackagePath: "example.com/app/impl", Module: "example.com/app"},
+				{Struct: "Vendored", PackagePath: "example.com/blob/s3", Module: "example.com/blob", Version: "v1.1.0"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			root := filepath.Join(dir, "app")
+			modCache := filepath.Join(dir, "modcache")
+
+			writeTree(t, root, app)
+			writeTree(t, modCache, map[string]string{
+				"example.com/blob@v1.2.0/go.mod":                    "module example.com/blob\n",
+				"example.com/blob@v1.2.0/s3/s3.go":                  store("s3", "Client"),
+				"example.com/blob@v1.2.0/internal/testdata/fake.go": store("fake", "Fake"),
+				"example.com/blob@v1.2.0/tools/go.mod":              "module example.com/blob/tools\n",
+				"example.com/blob@v1.2.0/tools/tools.go":            store("tools", "Tool"),
+			})
+			writeTree(t, dir, map[string]string{
+				"local/go.mod":       "module example.com/local\n",
+				"local/disk/disk.go": store("disk", "Disk"),
+			})
+
+			if tc.vendor {
+				writeTree(t, root, map[string]string{
+					"vendor/modules.txt":               "# example.com/blob v1.1.0\n## explicit\nexample.com/blob/s3\n",
+					"vendor/example.com/blob/s3/s3.go": store("s3", "Vendored"),
+				})
+			}
+
+			finder := newModuleFinder(t, root, "BlobStore")
+			finder.modCache = modCache
+			finder.deps = true
+
+			require.NoError(t, finder.loadInterface(interfaceSpec{
+				File: filepath.Join(root, "app", "app.go"),
+				Name: "BlobStore",
+			}))
+			require.NoError(t, finder.scanDirectory(root))
+
+			var got []Implementation
+			for _, impl := range finder.getResults() {
+				got = append(got, Implementation{
+					Struct:      impl.Struct,
+					PackagePath: impl.PackagePath,
+					Module:      impl.Module,
+					Version:     impl.Version,
+				})
+			}
+
+			slices.SortFunc(got, func(a, b Implementation) int {
+				return strings.Compare(a.Struct, b.Struct)
+			})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFinder_StdPackages(t *testing.T) {
+	t.Parallel()
+
+	finder := NewFinder("ReaderAt")
+
+	paths := make(map[string]bool)
+	for _, pkg := range finder.stdPackages() {
+		assert.Equal(t, stdModule, pkg.Module)
+		paths[pkg.ImportPath] = true
+	}
+
+	assert.True(t, paths["bytes"])
+	assert.True(t, paths["net/http"])
+
+	for path := range paths {
+		assert.NotContains(t, path, "internal")
+		assert.False(t, hasPathPrefix(path, "cmd"), path)
+		assert.False(t, hasPathPrefix(path, "vendor"), path)
+	}
+}
+
+func TestFinder_ScanStd(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Sized interface {
+	Len() int
+	Size() int64
+}
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Sized")
+	finder.std = true
+
+	require.NoError(t, finder.loadInterface(interfaceSpec{
+		File: filepath.Join(root, "app", "app.go"),
+		Name: "Sized",
+	}))
+	require.NoError(t, finder.scanDirectory(root))
+
+	var got []string
+
+	for _, impl := range finder.getResults() {
+		assert.Equal(t, stdModule, impl.Module)
+		assert.Empty(t, impl.Version)
+		got = append(got, impl.PackagePath+"."+impl.Struct)
+	}
+
+	assert.Contains(t, got, "bytes.Reader")
+	assert.Contains(t, got, "strings.Reader")
+}
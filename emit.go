@@ -0,0 +1,693 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// assertionFileName is the file -emit-assertions writes in each package.
+const assertionFileName = "zz_impl_assert.go"
+
+const generatedHeader = "// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.\n"
+
+// Modes of -emit-assertions.
+const (
+	emitWrite = "write"
+	emitDiff  = "diff"
+)
+
+// assertionFileChange is the new content of one package's assertion file.
+// Empty New means the file is no longer needed; empty Old that it is new.
+type assertionFileChange struct {
+	Path string
+	Old  []byte
+	New  []byte
+}
+
+// importSet hands out the local names the imports of a generated file use.
+type importSet struct {
+	pkg     *types.Package
+	names   map[string]string
+	pkgName map[string]string
+	taken   map[string]bool
+}
+
+func newImportSet(pkg *types.Package) *importSet {
+	return &importSet{
+		pkg:     pkg,
+		names:   make(map[string]string),
+		pkgName: make(map[string]string),
+		taken:   make(map[string]bool),
+	}
+}
+
+// reserve records an import under the name an existing line already uses.
+func (s *importSet) reserve(name string, pkg *types.Package) {
+	s.names[pkg.Path()] = name
+	s.pkgName[pkg.Path()] = pkg.Name()
+	s.taken[name] = true
+}
+
+// qualifier names pkg in the generated file, as a types.Qualifier. A name
+// clashing with another import or a declaration of the package gets a
+// numbered alias.
+func (s *importSet) qualifier(pkg *types.Package) string {
+	if pkg.Path() == s.pkg.Path() {
+		return ""
+	}
+
+	if name, ok := s.names[pkg.Path()]; ok {
+		return name
+	}
+
+	name := pkg.Name()
+	for i := 2; s.taken[name] || s.pkg.Scope().Lookup(name) != nil; i++ {
+		name = fmt.Sprintf("%s%d", pkg.Name(), i)
+	}
+
+	s.reserve(name, pkg)
+
+	return name
+}
+
+// source renders the import declaration, with an alias wherever the local
+// name isn't both the package's name and the last element of its path.
+func (s *importSet) source() string {
+	if len(s.names) == 0 {
+		return ""
+	}
+
+	paths := make([]string, 0, len(s.names))
+	for importPath := range s.names {
+		paths = append(paths, importPath)
+	}
+
+	// Standard library first, in its own group, the way goimports does it.
+	slices.SortFunc(paths, func(a, b string) int {
+		if isStdImportPath(a) != isStdImportPath(b) {
+			if isStdImportPath(a) {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	var sb strings.Builder
+
+	sb.WriteString("import (\n")
+
+	for i, importPath := range paths {
+		if i > 0 && isStdImportPath(paths[i-1]) && !isStdImportPath(importPath) {
+			sb.WriteString("\n")
+		}
+
+		name := s.names[importPath]
+		if name == s.pkgName[importPath] && name == path.Base(importPath) {
+			fmt.Fprintf(&sb, "\t%q\n", importPath)
+		} else {
+			fmt.Fprintf(&sb, "\t%s %q\n", name, importPath)
+		}
+	}
+
+	sb.WriteString(")\n\n")
+
+	return sb.String()
+}
+
+// assertionFileChanges works out the assertion file of every package with
+// implementations that are not asserted yet. Assertions the file already
+// holds are kept as long as they hold, and stale ones for the target are
+// dropped.
+func (f *Finder) assertionFileChanges() ([]assertionFileChange, error) {
+	targetType, err := f.assertionTargetType()
+	if err != nil {
+		return nil, err
+	}
+
+	byPackage := make(map[string][]Implementation)
+
+	for _, impl := range f.results {
+		if needsAssertion(impl) {
+			byPackage[impl.PackagePath] = append(byPackage[impl.PackagePath], impl)
+		}
+	}
+
+	// Scanned packages whose assertion file exists need a look too, in case
+	// all of its lines for the target went stale.
+	for importPath, pkg := range f.packages {
+		if f.scanned[pkg] && f.assertionFile(pkg) != nil {
+			if _, ok := byPackage[importPath]; !ok {
+				byPackage[importPath] = nil
+			}
+		}
+	}
+
+	importPaths := make([]string, 0, len(byPackage))
+	for importPath := range byPackage {
+		importPaths = append(importPaths, importPath)
+	}
+
+	slices.Sort(importPaths)
+
+	changes := make([]assertionFileChange, 0, len(importPaths))
+
+	for _, importPath := range importPaths {
+		change, ok, err := f.assertionFileChange(importPath, targetType, byPackage[importPath])
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// assertionTargetType is the interface as assertions spell it: generic ones
+// need the concrete type arguments of the spec.
+func (f *Finder) assertionTargetType() (types.Type, error) {
+	if f.target.TypeParams().Len() == 0 {
+		return f.target, nil
+	}
+
+	if f.ifaceGeneric != nil {
+		return nil, ErrEmitWildcard
+	}
+
+	typeArgs, _, err := f.evalTypeArgs(f.target.Obj().Pkg(), f.typeArgSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := types.Instantiate(nil, f.target, typeArgs, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTypeArg, err)
+	}
+
+	return instance, nil
+}
+
+// needsAssertion reports whether impl should get a generated assertion: it
+// implements the interface, lives outside test files, is not generic, and
+// isn't validly asserted yet. A valid line of the generated file is kept as
+// it is.
+func needsAssertion(impl Implementation) bool {
+	if !impl.ValueImplements && !impl.PointerImplements || impl.InTest || len(impl.TypeArgs) > 0 {
+		return false
+	}
+
+	for _, assertion := range impl.Assertions {
+		if assertion.Valid {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *Finder) assertionFileChange(
+	importPath string, targetType types.Type, impls []Implementation,
+) (assertionFileChange, bool, error) {
+	pkg := f.packages[importPath]
+	if pkg == nil || len(f.checkedFiles[pkg]) == 0 {
+		return assertionFileChange{}, false, nil
+	}
+
+	dir := filepath.Dir(f.fset.Position(f.checkedFiles[pkg][0].Package).Filename)
+	change := assertionFileChange{Path: filepath.Join(dir, assertionFileName)}
+
+	old, err := os.ReadFile(change.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return assertionFileChange{}, false, fmt.Errorf("failed to read %s: %w", change.Path, err)
+	}
+
+	change.Old = old
+
+	imports := newImportSet(pkg)
+	lines := f.keptAssertionLines(pkg, imports)
+	ifaceText := ""
+
+	if reason := f.importRestriction(pkg, targetType); reason != "" && len(impls) > 0 {
+		slog.Debug("skipping assertions of package that cannot refer to the interface",
+			"package", importPath, "reason", reason)
+
+		impls = nil
+	}
+
+	for _, impl := range impls {
+		if impl.Package != pkg.Name() {
+			continue
+		}
+
+		typeName, ok := pkg.Scope().Lookup(impl.Struct).(*types.TypeName)
+		if !ok {
+			continue
+		}
+
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+
+		if constraint := f.buildConstraint(typeName, f.checkedFiles[pkg]); constraint != "" {
+			slog.Debug("skipping assertion of type declared under a build constraint",
+				"type", importPath+"."+impl.Struct, "constraint", constraint)
+
+			continue
+		}
+
+		if ifaceText == "" {
+			ifaceText = types.TypeString(targetType, imports.qualifier)
+		}
+
+		value := assertionValue(types.TypeString(named, imports.qualifier), named, !impl.ValueImplements)
+		lines = append(lines, fmt.Sprintf("_ %s = %s", ifaceText, value))
+	}
+
+	slices.Sort(lines)
+	lines = slices.Compact(lines)
+
+	if len(lines) > 0 {
+		var src strings.Builder
+
+		fmt.Fprintf(&src, "%s\npackage %s\n\n%svar (\n", generatedHeader, pkg.Name(), imports.source())
+
+		for _, line := range lines {
+			src.WriteString("\t" + line + "\n")
+		}
+
+		src.WriteString(")\n")
+
+		change.New, err = format.Source([]byte(src.String()))
+		if err != nil {
+			return assertionFileChange{}, false, fmt.Errorf("failed to format %s: %w", change.Path, err)
+		}
+	}
+
+	return change, !bytes.Equal(change.Old, change.New), nil
+}
+
+// importRestriction tells why pkg cannot spell targetType in its assertions,
+// or returns "" when it can. The interface and the named types among its type
+// arguments must all be visible from pkg: declared outside test files, which
+// the assertion file does not build with, and exported from an importable
+// package when they live in another one.
+func (f *Finder) importRestriction(pkg *types.Package, targetType types.Type) string {
+	for _, obj := range namedTypes(targetType, nil) {
+		switch {
+		case obj.Pkg() == nil:
+			continue
+		case strings.HasSuffix(f.fset.Position(obj.Pos()).Filename, "_test.go"):
+			return obj.Name() + " is declared in a test file"
+		case obj.Pkg().Path() == pkg.Path():
+			continue
+		case !obj.Exported():
+			return obj.Name() + " is unexported"
+		}
+
+		if reason := packageRestriction(pkg, obj.Pkg()); reason != "" {
+			return reason
+		}
+	}
+
+	return ""
+}
+
+// packageRestriction tells why pkg cannot import other, or returns "" when it
+// can: a main package is not importable, importing a package that imports pkg
+// would be a cycle, and an internal package is only visible from the tree its
+// parent roots.
+func packageRestriction(pkg, other *types.Package) string {
+	switch {
+	case other.Name() == "main":
+		return other.Path() + " is package main"
+	case importsPackage(other, pkg.Path(), make(map[*types.Package]bool)):
+		return other.Path() + " imports it"
+	case !internalVisible(pkg.Path(), other.Path()):
+		return other.Path() + " is internal to another tree"
+	default:
+		return ""
+	}
+}
+
+// namedTypes appends the type names typ spells out, its own and those of its
+// type arguments and element types, to names.
+func namedTypes(typ types.Type, names []*types.TypeName) []*types.TypeName {
+	switch typ := types.Unalias(typ).(type) {
+	case *types.Named:
+		names = append(names, typ.Obj())
+
+		for i := range typ.TypeArgs().Len() {
+			names = namedTypes(typ.TypeArgs().At(i), names)
+		}
+	case *types.Pointer:
+		names = namedTypes(typ.Elem(), names)
+	case *types.Slice:
+		names = namedTypes(typ.Elem(), names)
+	case *types.Array:
+		names = namedTypes(typ.Elem(), names)
+	case *types.Map:
+		names = namedTypes(typ.Elem(), namedTypes(typ.Key(), names))
+	case *types.Chan:
+		names = namedTypes(typ.Elem(), names)
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{typ.Params(), typ.Results()} {
+			for i := range tuple.Len() {
+				names = namedTypes(tuple.At(i).Type(), names)
+			}
+		}
+	case *types.Struct:
+		for i := range typ.NumFields() {
+			names = namedTypes(typ.Field(i).Type(), names)
+		}
+	}
+
+	return names
+}
+
+// importsPackage reports whether pkg imports importPath, directly or not.
+func importsPackage(pkg *types.Package, importPath string, seen map[*types.Package]bool) bool {
+	if seen[pkg] {
+		return false
+	}
+
+	seen[pkg] = true
+
+	for _, imported := range pkg.Imports() {
+		if imported.Path() == importPath || importsPackage(imported, importPath, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// internalVisible applies the go command's internal/ rule: a path with an
+// "internal" element can only be imported from below that element's parent,
+// the last such element counting.
+func internalVisible(importer, importPath string) bool {
+	var parent string
+
+	switch {
+	case strings.HasSuffix(importPath, "/internal"):
+		parent = strings.TrimSuffix(importPath, "/internal")
+	case strings.Contains(importPath, "/internal/"):
+		parent = importPath[:strings.LastIndex(importPath, "/internal/")]
+	case importPath == "internal" || strings.HasPrefix(importPath, "internal/"):
+		return isStdImportPath(importer)
+	default:
+		return true
+	}
+
+	return hasPathPrefix(importer, parent)
+}
+
+// buildConstraint returns the //go:build line or the GOOS/GOARCH file name
+// suffix restricting the file that declares obj, or "" when the file always
+// builds. Such types are left out of the assertion file, which has no
+// constraint and would stop compiling everywhere else.
+func (f *Finder) buildConstraint(obj types.Object, files []*ast.File) string {
+	filename := f.fset.Position(obj.Pos()).Filename
+
+	for _, file := range files {
+		if f.fset.Position(file.Package).Filename != filename {
+			continue
+		}
+
+		for _, group := range file.Comments {
+			if group.Pos() > file.Package {
+				break
+			}
+
+			for _, comment := range group.List {
+				if constraint.IsGoBuild(comment.Text) || constraint.IsPlusBuild(comment.Text) {
+					return comment.Text
+				}
+			}
+		}
+	}
+
+	if hasOSArchSuffix(filename) {
+		return filepath.Base(filename)
+	}
+
+	return ""
+}
+
+// hasOSArchSuffix reports whether the file name ends in a _GOOS, _GOARCH or
+// _GOOS_GOARCH suffix. It matches the name against a platform no file is
+// named for, reading every file as unconstrained, so only the name decides.
+func hasOSArchSuffix(filename string) bool {
+	buildContext := build.Context{
+		GOOS:     "none",
+		GOARCH:   "none",
+		Compiler: "gc",
+		OpenFile: func(string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("package p\n")), nil
+		},
+	}
+
+	match, err := buildContext.MatchFile(filepath.Dir(filename), filepath.Base(filename))
+
+	return err == nil && !match
+}
+
+// assertionFile returns pkg's existing assertion file, if it has one.
+func (f *Finder) assertionFile(pkg *types.Package) *ast.File {
+	for _, file := range f.checkedFiles[pkg] {
+		if filepath.Base(f.fset.Position(file.Package).Filename) == assertionFileName {
+			return file
+		}
+	}
+
+	return nil
+}
+
+// keptAssertionLines returns the lines of pkg's assertion file that assert
+// other interfaces than the target, or the target and still hold, reserving
+// the import names they use. Keeping the valid target lines leaves the ones a
+// filtered run does not report in place.
+func (f *Finder) keptAssertionLines(pkg *types.Package, imports *importSet) []string {
+	file := f.assertionFile(pkg)
+	if file == nil {
+		return nil
+	}
+
+	valid := make(map[string]bool)
+
+	for _, asserted := range f.assertions {
+		for _, entry := range asserted {
+			if entry.assertion.Valid {
+				valid[entry.assertion.Position] = true
+			}
+		}
+	}
+
+	var lines []string
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok || valueSpec.Type == nil || len(valueSpec.Values) != 1 {
+				continue
+			}
+
+			declared, ok := types.Unalias(f.info.TypeOf(valueSpec.Type)).(*types.Named)
+			if ok && declared.Origin() == f.target.Origin() &&
+				!valid[f.positionString(valueSpec.Values[0].Pos())] {
+				continue
+			}
+
+			ast.Inspect(valueSpec, func(node ast.Node) bool {
+				if ident, ok := node.(*ast.Ident); ok {
+					if pkgName, ok := f.info.Uses[ident].(*types.PkgName); ok {
+						imports.reserve(pkgName.Name(), pkgName.Imported())
+					}
+				}
+
+				return true
+			})
+
+			lines = append(lines, fmt.Sprintf("_ %s = %s",
+				f.nodeString(valueSpec.Type), f.nodeString(valueSpec.Values[0])))
+		}
+	}
+
+	return lines
+}
+
+func (f *Finder) nodeString(node ast.Node) string {
+	var buf bytes.Buffer
+
+	if err := printer.Fprint(&buf, f.fset, node); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// assertionValue is the zero value asserted for named: a value of it when
+// values implement the interface, which covers pointers too, otherwise a nil
+// pointer to it.
+func assertionValue(name string, named *types.Named, pointer bool) string {
+	if pointer {
+		return "(*" + name + ")(nil)"
+	}
+
+	switch underlying := named.Underlying().(type) {
+	case *types.Struct, *types.Array:
+		return name + "{}"
+	case *types.Basic:
+		switch {
+		case underlying.Info()&types.IsBoolean != 0:
+			return name + "(false)"
+		case underlying.Info()&types.IsString != 0:
+			return name + `("")`
+		case underlying.Info()&types.IsNumeric != 0:
+			return name + "(0)"
+		}
+	}
+
+	return name + "(nil)"
+}
+
+// applyAssertionFileChanges writes or removes the assertion files.
+func applyAssertionFileChanges(changes []assertionFileChange) error {
+	for _, change := range changes {
+		if len(change.New) == 0 {
+			if err := os.Remove(change.Path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+			}
+
+			continue
+		}
+
+		if err := os.WriteFile(change.Path, change.New, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// writeAssertionDiff prints the changes as a unified diff.
+func (f *Finder) writeAssertionDiff(w io.Writer, changes []assertionFileChange) error {
+	var sb strings.Builder
+
+	for _, change := range changes {
+		name := change.Path
+
+		root, rootErr := filepath.Abs(f.moduleRoot)
+		abs, absErr := filepath.Abs(change.Path)
+
+		if rootErr == nil && absErr == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				name = filepath.ToSlash(rel)
+			}
+		}
+
+		sb.WriteString(unifiedDiff(name, string(change.Old), string(change.New)))
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write diff: %w", err)
+	}
+
+	return nil
+}
+
+// unifiedDiff renders the change from old to new as one hunk covering the
+// whole file; assertion files are short enough for that.
+func unifiedDiff(name, oldText, newText string) string {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	var sb strings.Builder
+
+	oldName, newName := "a/"+name, "b/"+name
+	if len(oldLines) == 0 {
+		oldName = "/dev/null"
+	}
+
+	if len(newLines) == 0 {
+		newName = "/dev/null"
+	}
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n@@ -%s +%s @@\n",
+		oldName, newName, hunkRange(len(oldLines)), hunkRange(len(newLines)))
+
+	// Longest common subsequence, so unchanged lines show as context.
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			sb.WriteString(" " + oldLines[i] + "\n")
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("-" + oldLines[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+" + newLines[j] + "\n")
+			j++
+		}
+	}
+
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func hunkRange(lines int) string {
+	if lines == 0 {
+		return "0,0"
+	}
+
+	return fmt.Sprintf("1,%d", lines)
+}
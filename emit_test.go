@@ -0,0 +1,642 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func emitModule(t *testing.T) string {
+	t.Helper()
+
+	return newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Server interface {
+	Start() error
+	Stop() error
+}
+`,
+		"impl/impl.go": `package impl
+
+import "example.com/app/app"
+
+type Web struct{}
+
+func (*Web) Start() error { return nil }
+func (*Web) Stop() error  { return nil }
+
+type Plain struct{}
+
+func (Plain) Start() error { return nil }
+func (Plain) Stop() error  { return nil }
+func (Plain) Close() error { return nil }
+
+type Pinned struct{}
+
+func (Pinned) Start() error { return nil }
+func (Pinned) Stop() error  { return nil }
+
+var _ app.Server = Pinned{}
+`,
+		"impl/zz_impl_assert.go": `// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.
+
+package impl
+
+import (
+	"io"
+
+	"example.com/app/app"
+)
+
+var (
+	_ app.Server = (*Gone)(nil)
+	_ io.Closer  = Plain{}
+)
+`,
+		"other/other.go": `package other
+
+type app struct{}
+
+type Thing struct{}
+
+func (Thing) Start() error { return nil }
+func (Thing) Stop() error  { return nil }
+`,
+	})
+}
+
+func TestFinder_AssertionFileChanges(t *testing.T) {
+	t.Parallel()
+
+	root := emitModule(t)
+
+	finder := newModuleFinder(t, root, "Server")
+	finder.enableAssertions()
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Server"}))
+	require.NoError(t, finder.scanDirectory(root))
+
+	changes, err := finder.assertionFileChanges()
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	expected := map[string]string{
+		filepath.Join(root, "impl", assertionFileName): `// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.
+
+package impl
+
+import (
+	"io"
+
+	"example.com/app/app"
+)
+
+var (
+	_ app.Server = (*Web)(nil)
+	_ app.Server = Plain{}
+	_ io.Closer  = Plain{}
+)
+`,
+		filepath.Join(root, "other", assertionFileName): `// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.
+
+package other
+
+import (
+	app2 "example.com/app/app"
+)
+
+var (
+	_ app2.Server = Thing{}
+)
+`,
+	}
+
+	for _, change := range changes {
+		assert.Equal(t, expected[change.Path], string(change.New), change.Path)
+	}
+
+	require.NoError(t, applyAssertionFileChanges(changes))
+
+	content, err := os.ReadFile(filepath.Join(root, "other", assertionFileName))
+	require.NoError(t, err)
+	assert.Equal(t, expected[filepath.Join(root, "other", assertionFileName)], string(content))
+
+	// A second run finds everything asserted and changes nothing.
+	finder = newModuleFinder(t, root, "Server")
+	finder.enableAssertions()
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Server"}))
+	require.NoError(t, finder.scanDirectory(root))
+
+	changes, err = finder.assertionFileChanges()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestFinder_AssertionFileChangesWildcard(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Getter[T any] interface {
+	Get() T
+}
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Getter")
+	finder.enableAssertions()
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Getter"}))
+	require.NoError(t, finder.scanDirectory(root))
+
+	_, err := finder.assertionFileChanges()
+	require.ErrorIs(t, err, ErrEmitWildcard)
+}
+
+func TestFinder_AssertionFileChangesImportRestrictions(t *testing.T) {
+	t.Parallel()
+
+	impl := `package srv
+
+type S struct{}
+
+func (S) Run() error { return nil }
+`
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		spec     interfaceSpec
+		expected []string
+	}{
+		{
+			name: "interface in package main",
+			files: map[string]string{
+				"main.go": `package main
+
+type Runner interface{ Run() error }
+
+func main() {}
+`,
+				"srv/srv.go": impl,
+			},
+			spec: interfaceSpec{File: "main.go", Name: "Runner"},
+		},
+		{
+			name: "interface package imports the implementation",
+			files: map[string]string{
+				"api/api.go": `package api
+
+import "example.com/app/srv"
+
+type Runner interface{ Run() error }
+
+var Default = srv.S{}
+`,
+				"srv/srv.go": impl,
+			},
+			spec: interfaceSpec{ImportPath: "example.com/app/api", Name: "Runner"},
+		},
+		{
+			name: "interface package imports the implementation transitively",
+			files: map[string]string{
+				"api/api.go": `package api
+
+import "example.com/app/wire"
+
+type Runner interface{ Run() error }
+
+var Default = wire.Default
+`,
+				"wire/wire.go": `package wire
+
+import "example.com/app/srv"
+
+var Default = srv.S{}
+`,
+				"srv/srv.go": impl,
+			},
+			spec: interfaceSpec{ImportPath: "example.com/app/api", Name: "Runner"},
+		},
+		{
+			name: "internal package of another tree",
+			files: map[string]string{
+				"core/internal/api/api.go": `package api
+
+type Runner interface{ Run() error }
+`,
+				"srv/srv.go": impl,
+			},
+			spec: interfaceSpec{ImportPath: "example.com/app/core/internal/api", Name: "Runner"},
+		},
+		{
+			name: "internal package of the same tree",
+			files: map[string]string{
+				"core/internal/api/api.go": `package api
+
+type Runner interface{ Run() error }
+`,
+				"core/srv/srv.go": impl,
+			},
+			spec:     interfaceSpec{ImportPath: "example.com/app/core/internal/api", Name: "Runner"},
+			expected: []string{filepath.Join("core", "srv", assertionFileName)},
+		},
+		{
+			name: "unexported interface of another package",
+			files: map[string]string{
+				"api/api.go": `package api
+
+type runner interface{ Run() error }
+`,
+				"srv/srv.go": impl,
+			},
+			spec: interfaceSpec{ImportPath: "example.com/app/api", Name: "runner"},
+		},
+		{
+			name: "interface declared in a test file",
+			files: map[string]string{
+				"srv/srv.go": impl,
+				"srv/runner_test.go": `package srv
+
+type Runner interface{ Run() error }
+`,
+			},
+			spec: interfaceSpec{File: "srv/runner_test.go", Name: "Runner"},
+		},
+		{
+			name: "unexported type argument of another package",
+			files: map[string]string{
+				"api/api.go": `package api
+
+type Getter[T any] interface{ Get() T }
+
+type item struct{}
+
+type Base struct{}
+
+func (Base) Get() item { return item{} }
+`,
+				"srv/srv.go": `package srv
+
+import "example.com/app/api"
+
+type S struct{ api.Base }
+`,
+			},
+			spec:     interfaceSpec{ImportPath: "example.com/app/api", Name: "Getter", TypeArgs: []string{"item"}},
+			expected: []string{filepath.Join("api", assertionFileName)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := newTempModule(t, tc.files)
+
+			if tc.spec.File != "" {
+				tc.spec.File = filepath.Join(root, tc.spec.File)
+			}
+
+			finder := newModuleFinder(t, root, tc.spec.Name)
+			finder.enableAssertions()
+			require.NoError(t, finder.loadInterface(tc.spec))
+			require.NoError(t, finder.scanDirectory(root))
+			require.NotEmpty(t, finder.getResults())
+
+			changes, err := finder.assertionFileChanges()
+			require.NoError(t, err)
+
+			var paths []string
+			for _, change := range changes {
+				rel, err := filepath.Rel(root, change.Path)
+				require.NoError(t, err)
+
+				paths = append(paths, rel)
+			}
+
+			assert.Equal(t, tc.expected, paths)
+		})
+	}
+}
+
+func TestFinder_AssertionFileChangesFiltered(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"app/app.go": `package app
+
+type Server interface {
+	Start() error
+}
+`,
+		"impl/impl.go": `package impl
+
+type Good struct{}
+
+func (*Good) Start() error { return nil }
+
+type Handler func() error
+
+func (h Handler) Start() error { return h() }
+
+type Broken struct{}
+`,
+		"impl/zz_impl_assert.go": `// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.
+
+package impl
+
+import (
+	"example.com/app/app"
+)
+
+var (
+	_ app.Server = (*Good)(nil)
+	_ app.Server = Broken{}
+)
+`,
+	}
+
+	withHandler := `// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.
+
+package impl
+
+import (
+	"example.com/app/app"
+)
+
+var (
+	_ app.Server = (*Good)(nil)
+	_ app.Server = Handler(nil)
+)
+`
+
+	testCases := []struct {
+		name      string
+		configure func(t *testing.T, finder *Finder)
+		expected  string
+	}{
+		{
+			name: "kinds",
+			configure: func(_ *testing.T, finder *Finder) {
+				finder.kinds = map[string]bool{kindFunc: true}
+			},
+			expected: withHandler,
+		},
+		{
+			name: "value only",
+			configure: func(_ *testing.T, finder *Finder) {
+				finder.valueOnly = true
+			},
+			expected: withHandler,
+		},
+		{
+			name: "exclude",
+			configure: func(t *testing.T, finder *Finder) {
+				filter, err := newPathFilter([]string{"impl/impl.go"}, nil, false)
+				require.NoError(t, err)
+
+				finder.filter = filter
+			},
+			expected: `// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.
+
+package impl
+
+import (
+	"example.com/app/app"
+)
+
+var (
+	_ app.Server = (*Good)(nil)
+)
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := newTempModule(t, files)
+
+			finder := newModuleFinder(t, root, "Server")
+			finder.enableAssertions()
+			tc.configure(t, finder)
+			require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Server"}))
+			require.NoError(t, finder.scanDirectory(root))
+
+			changes, err := finder.assertionFileChanges()
+			require.NoError(t, err)
+			require.Len(t, changes, 1)
+
+			// Good is filtered out of the results but its line still holds;
+			// the stale Broken line goes.
+			assert.Equal(t, tc.expected, string(changes[0].New))
+		})
+	}
+}
+
+func TestFinder_AssertionFileChangesBuildConstraints(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"api/api.go": `package api
+
+type Runner interface{ Run() error }
+`,
+		"srv/srv.go": `package srv
+
+type S struct{}
+
+func (S) Run() error { return nil }
+`,
+		"srv/l_linux.go": `package srv
+
+type L struct{}
+
+func (L) Run() error { return nil }
+`,
+		"srv/tagged.go": `//go:build linux || darwin
+
+package srv
+
+type Tagged struct{}
+
+func (Tagged) Run() error { return nil }
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Runner")
+	finder.setBuildTarget("linux", "amd64", nil)
+	finder.enableAssertions()
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/api", Name: "Runner"}))
+	require.NoError(t, finder.scanDirectory(root))
+
+	var names []string
+	for _, impl := range finder.getResults() {
+		names = append(names, impl.Struct)
+	}
+
+	assert.ElementsMatch(t, []string{"S", "L", "Tagged"}, names)
+
+	changes, err := finder.assertionFileChanges()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	assert.Equal(t, `// Code generated by gofindimpl -emit-assertions. DO NOT EDIT.
+
+package srv
+
+import (
+	"example.com/app/api"
+)
+
+var (
+	_ api.Runner = S{}
+)
+`, string(changes[0].New))
+}
+
+func TestHasOSArchSuffix(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]bool{
+		"l_linux.go":         true,
+		"l_amd64.go":         true,
+		"l_windows_arm64.go": true,
+		"l.go":               false,
+		"linux.go":           false,
+		"l_server.go":        false,
+	}
+
+	for filename, expected := range testCases {
+		t.Run(filename, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, expected, hasOSArchSuffix(filepath.Join("srv", filename)))
+		})
+	}
+}
+
+func TestInternalVisible(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		importer   string
+		importPath string
+		expected   bool
+	}{
+		{"example.com/app/srv", "example.com/app/api", true},
+		{"example.com/app/srv", "example.com/app/internal/api", true},
+		{"example.com/app", "example.com/app/internal", true},
+		{"example.com/app/srv", "example.com/app/core/internal/api", false},
+		{"example.com/app/core/srv", "example.com/app/core/internal/api", true},
+		{"example.com/app/core/internal/x", "example.com/app/core/internal/api/internal/y", false},
+		{"example.com/app/srv", "internal/poll", false},
+		{"os", "internal/poll", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.importer+" imports "+tc.importPath, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, internalVisible(tc.importer, tc.importPath))
+		})
+	}
+}
+
+func TestAssertionValue(t *testing.T) {
+	t.Parallel()
+
+	pkg := checkSource(t, "example.com/p", `package p
+
+type S struct{}
+type A [2]int
+type F func()
+type M map[string]int
+type N int
+type Str string
+type B bool
+`)
+
+	testCases := []struct {
+		name     string
+		typeName string
+		pointer  bool
+		expected string
+	}{
+		{name: "struct", typeName: "S", expected: "S{}"},
+		{name: "pointer", typeName: "S", pointer: true, expected: "(*S)(nil)"},
+		{name: "array", typeName: "A", expected: "A{}"},
+		{name: "func", typeName: "F", expected: "F(nil)"},
+		{name: "map", typeName: "M", expected: "M(nil)"},
+		{name: "number", typeName: "N", expected: "N(0)"},
+		{name: "string", typeName: "Str", expected: `Str("")`},
+		{name: "bool", typeName: "B", expected: "B(false)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			named := lookupNamed(t, pkg, tc.typeName)
+			assert.Equal(t, tc.expected, assertionValue(tc.typeName, named, tc.pointer))
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		oldText  string
+		newText  string
+		expected string
+	}{
+		{
+			name:     "new file",
+			newText:  "a\nb\n",
+			expected: "--- /dev/null\n+++ b/x.go\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:     "removed file",
+			oldText:  "a\n",
+			expected: "--- a/x.go\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-a\n",
+		},
+		{
+			name:     "changed line",
+			oldText:  "a\nb\nc\n",
+			newText:  "a\nB\nc\nd\n",
+			expected: "--- a/x.go\n+++ b/x.go\n@@ -1,3 +1,4 @@\n a\n-b\n+B\n c\n+d\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, unifiedDiff("x.go", tc.oldText, tc.newText))
+		})
+	}
+}
+
+func TestFinder_WriteAssertionDiff(t *testing.T) {
+	t.Parallel()
+
+	finder := NewFinder("Server")
+	finder.moduleRoot = "/src/app"
+
+	var buf bytes.Buffer
+	require.NoError(t, finder.writeAssertionDiff(&buf, []assertionFileChange{
+		{Path: "/src/app/impl/" + assertionFileName, New: []byte("package impl\n")},
+	}))
+
+	assert.Equal(t,
+		"--- /dev/null\n+++ b/impl/zz_impl_assert.go\n@@ -0,0 +1,1 @@\n+package impl\n",
+		buf.String())
+}
@@ -4,8 +4,9 @@ import "errors"
 
 var (
 	ErrGoModNotFound = errors.New(
-		"go.mod not found in current directory - must launch from go module root")
+		"go.mod not found in current directory or any parent - must launch from inside a go module")
 	ErrNoModuleDeclaration   = errors.New("no module declaration found in go.mod")
+	ErrNoWorkspaceModules    = errors.New("go.work does not use any module")
 	ErrInterfaceFileRequired = errors.New(
 		"interface file is required. Use -interface flag")
 	ErrInterfaceNameRequired = errors.New(
@@ -14,11 +15,53 @@ var (
 	ErrNoFilesToTypeCheck = errors.New("no files to type check")
 	ErrInterfaceSpecEmpty = errors.New(
 		"interface specification cannot be empty. " +
-			"Use -interface flag with format 'file.go:InterfaceName'")
+			"Use -interface flag with format 'file.go:InterfaceName' " +
+			"or 'importpath.InterfaceName'")
 	ErrInterfaceSpecFormat = errors.New(
-		"interface specification must be in format 'file.go:InterfaceName'")
+		"interface specification must be in format 'file.go:InterfaceName', " +
+			"'importpath.InterfaceName' or 'importpath:InterfaceName'")
 	ErrInterfaceFilePathEmpty = errors.New("interface file path cannot be empty")
 	ErrInterfaceNameEmpty     = errors.New("interface name cannot be empty")
 	ErrInterfaceFileNotExist  = errors.New("interface file does not exist")
 	ErrSearchDirNotExist      = errors.New("search directory does not exist")
+	ErrPatternNoModule        = errors.New("package pattern matches no module")
+	ErrDirWithPatterns        = errors.New("-dir cannot be combined with package patterns")
+	ErrInvalidPathPattern     = errors.New("invalid -exclude or -include pattern")
+	ErrImportNotFound         = errors.New("cannot resolve import")
+	ErrImportCycle            = errors.New("import cycle")
+	ErrUnresolvedEmbed        = errors.New("embedded interface could not be resolved")
+	ErrTypeArgCount           = errors.New("wrong number of type arguments")
+	ErrInvalidTypeArg         = errors.New("invalid type argument")
+	ErrTypeNotFound           = errors.New("type not found in package")
+	ErrTypeSpecFormat         = errors.New(
+		"type specification must be in format 'importpath.TypeName' or 'importpath:TypeName'")
+	ErrUnknownFormat       = errors.New("unknown output format, expected json or text")
+	ErrNegativeNearMiss    = errors.New("near-miss threshold cannot be negative")
+	ErrPlatformFormat      = errors.New("platform must be in format 'GOOS/GOARCH'")
+	ErrPlatformsWithTarget = errors.New(
+		"-platforms cannot be combined with -goos or -goarch")
+	ErrPlatformsWithExplain = errors.New("-platforms cannot be combined with -explain")
+	ErrTypeWithInterface    = errors.New("-type and -interface cannot be combined")
+	ErrPlatformsWithType    = errors.New("-platforms cannot be combined with -type")
+	ErrMissingAssertions    = errors.New("implementations without a compile-time assertion")
+	ErrStaleAssertions      = errors.New("assertions of types that do not implement the interface")
+	ErrEmitWildcard         = errors.New(
+		"-emit-assertions needs concrete type arguments for a generic interface",
+	)
+	ErrUnknownEmitMode = errors.New("unknown -emit-assertions mode, expected write or diff")
+	ErrEmitConflict    = errors.New(
+		"-emit-assertions cannot be combined with -explain, -hierarchy, -platforms, -deps or -std",
+	)
+	ErrHierarchyConflict = errors.New(
+		"-hierarchy cannot be combined with -explain, -near-miss, -kinds, -value-only or -platforms",
+	)
+	ErrReverseConflict = errors.New(
+		"-type cannot be combined with -explain, -hierarchy, -assertions, -require-assertions, " +
+			"-emit-assertions, -near-miss, -kinds, -value-only or -format",
+	)
+	ErrInterfacesConflict = errors.New(
+		"several interfaces cannot be combined with -explain, -hierarchy or -emit-assertions",
+	)
+	ErrUnknownKind = errors.New(
+		"unknown kind, expected struct, func, slice, array, map, basic, pointer, chan or interface")
 )
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+	"strings"
+)
+
+// Output formats accepted by -format.
+const (
+	formatJSON = "json"
+	formatText = "text"
+)
+
+// statusOK marks an interface method the type provides as required.
+const statusOK = "ok"
+
+// Receiver kinds of a method that provides an interface method.
+const (
+	receiverValue     = "value"
+	receiverPointer   = "pointer"
+	receiverInterface = "interface"
+)
+
+// Explanation is the -explain breakdown of whether one type implements the
+// target interface.
+type Explanation struct {
+	Type              string              `json:"type"`
+	Kind              string              `json:"kind"`
+	Interface         string              `json:"interface"`
+	Implements        bool                `json:"implements"`
+	ValueImplements   bool                `json:"valueImplements"`
+	PointerImplements bool                `json:"pointerImplements"`
+	TypeArgs          []string            `json:"typeArgs,omitempty"`
+	InterfaceTypeArgs []string            `json:"interfaceTypeArgs,omitempty"`
+	Methods           []MethodExplanation `json:"methods"`
+}
+
+// MethodExplanation tells how a type provides one method of the interface.
+// Status is statusOK or one of the near-miss reasons. Via lists the embedded
+// fields a promoted method comes through, outermost first, Depth is its
+// length and From the type declaring the method. Ambiguous lists the
+// colliding paths when two embedded fields at the same depth provide the
+// method.
+type MethodExplanation struct {
+	Method           string     `json:"method"`
+	Expected         string     `json:"expected"`
+	Status           string     `json:"status"`
+	Actual           string     `json:"actual,omitempty"`
+	Receiver         string     `json:"receiver,omitempty"`
+	InValueMethodSet bool       `json:"inValueMethodSet"`
+	Via              []string   `json:"via,omitempty"`
+	Depth            int        `json:"depth"`
+	From             string     `json:"from,omitempty"`
+	Ambiguous        [][]string `json:"ambiguous,omitempty"`
+}
+
+// explainType loads the type name from the package at importPath and
+// explains it against the target interface.
+func (f *Finder) explainType(importPath, name string) (Explanation, error) {
+	pkg, err := f.importPackage(importPath)
+	if err != nil {
+		return Explanation{}, fmt.Errorf(
+			"failed to load package %s: %w",
+			importPath,
+			err,
+		)
+	}
+
+	typeName, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return Explanation{}, fmt.Errorf("%w '%s' in %s",
+			ErrTypeNotFound, name, importPath)
+	}
+
+	namedType, ok := types.Unalias(typeName.Type()).(*types.Named)
+	if !ok {
+		return Explanation{}, fmt.Errorf("%w '%s' in %s",
+			ErrTypeNotFound, name, importPath)
+	}
+
+	match, implements := f.matchType(namedType)
+
+	return Explanation{
+		Type:              types.TypeString(namedType.Origin(), nil),
+		Kind:              typeKind(namedType),
+		Interface:         types.TypeString(f.target, nil),
+		Implements:        implements,
+		ValueImplements:   match.valueImplements,
+		PointerImplements: match.pointerImplements,
+		TypeArgs:          typeStrings(match.typeArgs),
+		InterfaceTypeArgs: typeStrings(match.interfaceTypeArgs),
+		Methods:           f.explainMethods(namedType),
+	}, nil
+}
+
+// explainMethods looks up each of the target's methods on namedType.
+// Generic candidates are unified method by method the way matchGeneric does,
+// so a signature only differs when no instantiation reconciles it with the
+// others.
+func (f *Finder) explainMethods(namedType *types.Named) []MethodExplanation {
+	if f.iface == nil {
+		return nil
+	}
+
+	candidateParams := typeParamList(namedType.TypeParams())
+
+	candidate, ok := selfInstance(namedType, candidateParams)
+	if !ok {
+		return nil
+	}
+
+	u := f.newMatchUnifier(candidateParams, f.targetTypeParams())
+	valueSet := types.NewMethodSet(candidate)
+	pointerSet := types.NewMethodSet(types.NewPointer(candidate))
+
+	methods := make([]MethodExplanation, 0, f.iface.NumMethods())
+
+	for method := range f.iface.Methods() {
+		explained := MethodExplanation{
+			Method:   method.Name(),
+			Expected: methodString(method),
+		}
+
+		selection := pointerSet.Lookup(method.Pkg(), method.Name())
+		if selection == nil {
+			f.explainAbsentMethod(&explained, candidate, pointerSet, method)
+			methods = append(methods, explained)
+
+			continue
+		}
+
+		fn, _ := selection.Obj().(*types.Func)
+		explained.Actual = methodString(fn)
+		explained.Receiver = receiverKind(fn)
+		explained.InValueMethodSet = valueSet.Lookup(method.Pkg(), method.Name()) != nil
+		explained.Via = embeddedPath(candidate, selection.Index())
+		explained.Depth = len(explained.Via)
+		explained.From = declaringType(fn)
+
+		explained.Status = statusOK
+		if !u.unify(method.Type(), fn.Type()) {
+			explained.Status = reasonSignature
+		}
+
+		methods = append(methods, explained)
+	}
+
+	return methods
+}
+
+// explainAbsentMethod fills in why a method is not in the candidate's method
+// set: an ambiguous selector, a near-identical name, or no such method.
+func (f *Finder) explainAbsentMethod(
+	explained *MethodExplanation,
+	candidate types.Type,
+	pointerSet *types.MethodSet,
+	method *types.Func,
+) {
+	if paths := selectorPaths(candidate, method.Pkg(), method.Name()); len(paths) > 1 {
+		explained.Status = reasonAmbiguous
+		explained.Ambiguous = paths
+		explained.Depth = len(paths[0])
+
+		return
+	}
+
+	similar := similarMethod(pointerSet, method)
+	if similar == nil {
+		explained.Status = reasonNotDeclared
+
+		return
+	}
+
+	explained.Status = reasonNameCase
+	if similar.Name() == method.Name() {
+		explained.Status = reasonOtherPackage
+	}
+
+	explained.Actual = methodString(similar)
+}
+
+// receiverKind classifies the receiver of a method: value, pointer, or
+// interface for methods of embedded interfaces.
+func receiverKind(fn *types.Func) string {
+	signature, ok := fn.Type().(*types.Signature)
+	if !ok || signature.Recv() == nil {
+		return ""
+	}
+
+	recv := signature.Recv().Type()
+
+	switch {
+	case types.IsInterface(recv):
+		return receiverInterface
+	case isPointer(recv):
+		return receiverPointer
+	default:
+		return receiverValue
+	}
+}
+
+// declaringType names the type a method is declared on, with its full
+// package path.
+func declaringType(fn *types.Func) string {
+	signature, ok := fn.Type().(*types.Signature)
+	if !ok || signature.Recv() == nil {
+		return ""
+	}
+
+	return types.TypeString(derefType(signature.Recv().Type()), nil)
+}
+
+func isPointer(typ types.Type) bool {
+	_, ok := typ.(*types.Pointer)
+
+	return ok
+}
+
+// embeddedPath turns a selection index into the names of the embedded fields
+// it walks through; the last index entry is the method itself.
+func embeddedPath(typ types.Type, index []int) []string {
+	if len(index) <= 1 {
+		return nil
+	}
+
+	path := make([]string, 0, len(index)-1)
+
+	for _, i := range index[:len(index)-1] {
+		st, ok := derefType(typ).Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+
+		field := st.Field(i)
+		path = append(path, field.Name())
+		typ = field.Type()
+	}
+
+	return path
+}
+
+// embeddedLevel is a type reached through embedded fields, with the field
+// names leading to it.
+type embeddedLevel struct {
+	typ  types.Type
+	path []string
+}
+
+// selectorPaths returns every shortest path of embedded fields leading to a
+// field or method called name. More than one path means the selector is
+// ambiguous, which is exactly what drops the method from the method set.
+func selectorPaths(typ types.Type, pkg *types.Package, name string) [][]string {
+	id := types.Id(pkg, name)
+	seen := make(map[*types.Named]bool)
+	level := []embeddedLevel{{typ: typ}}
+
+	for len(level) > 0 {
+		var (
+			found     [][]string
+			next      []embeddedLevel
+			levelSeen []*types.Named
+		)
+
+		for _, entry := range level {
+			current := derefType(entry.typ)
+
+			if named, ok := current.(*types.Named); ok {
+				// A type already searched at a shallower depth cannot add a
+				// shorter path; the same type twice at this depth can collide.
+				if seen[named.Origin()] {
+					continue
+				}
+
+				levelSeen = append(levelSeen, named.Origin())
+
+				for method := range named.Methods() {
+					if method.Id() == id {
+						found = append(found, entry.path)
+					}
+				}
+			}
+
+			switch underlying := current.Underlying().(type) {
+			case *types.Struct:
+				for field := range underlying.Fields() {
+					if field.Id() == id {
+						found = append(found, entry.path)
+					}
+
+					if field.Embedded() {
+						next = append(next, embeddedLevel{
+							typ:  field.Type(),
+							path: append(append([]string{}, entry.path...), field.Name()),
+						})
+					}
+				}
+			case *types.Interface:
+				for method := range underlying.Methods() {
+					if method.Id() == id {
+						found = append(found, entry.path)
+					}
+				}
+			}
+		}
+
+		if len(found) > 0 {
+			return found
+		}
+
+		for _, named := range levelSeen {
+			seen[named] = true
+		}
+
+		level = next
+	}
+
+	return nil
+}
+
+func derefType(typ types.Type) types.Type {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+
+	return typ
+}
+
+// writeExplanation renders an explanation as indented JSON or as text.
+func writeExplanation(w io.Writer, explanation Explanation, format string) error {
+	if format == formatText {
+		_, err := io.WriteString(w, explanationText(explanation))
+		if err != nil {
+			return fmt.Errorf("failed to write explanation: %w", err)
+		}
+
+		return nil
+	}
+
+	output, err := json.MarshalIndent(explanation, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal explanation to JSON: %w", err)
+	}
+
+	if _, err := w.Write(append(output, '\n')); err != nil {
+		return fmt.Errorf("failed to write explanation: %w", err)
+	}
+
+	return nil
+}
+
+func explanationText(explanation Explanation) string {
+	var sb strings.Builder
+
+	verdict := "does not implement"
+	if explanation.Implements {
+		verdict = "implements"
+	}
+
+	fmt.Fprintf(&sb, "%s (%s) %s %s\n",
+		explanation.Type, explanation.Kind, verdict, explanation.Interface)
+	fmt.Fprintf(&sb, "  value: %s, pointer: %s\n",
+		yesNo(explanation.ValueImplements), yesNo(explanation.PointerImplements))
+
+	if len(explanation.TypeArgs) > 0 {
+		fmt.Fprintf(&sb, "  type arguments: %s\n", strings.Join(explanation.TypeArgs, ", "))
+	}
+
+	if len(explanation.InterfaceTypeArgs) > 0 {
+		fmt.Fprintf(&sb, "  interface type arguments: %s\n",
+			strings.Join(explanation.InterfaceTypeArgs, ", "))
+	}
+
+	for _, method := range explanation.Methods {
+		fmt.Fprintf(&sb, "  %s: %s\n", method.Expected, methodText(method))
+	}
+
+	return sb.String()
+}
+
+func methodText(method MethodExplanation) string {
+	switch method.Status {
+	case statusOK, reasonSignature:
+		location := "declared directly"
+		if method.Depth > 0 {
+			location = fmt.Sprintf("promoted via %s (depth %d)",
+				strings.Join(method.Via, "."), method.Depth)
+		}
+
+		text := fmt.Sprintf("%s, %s receiver, %s", method.Status, method.Receiver, location)
+		if method.Status != statusOK {
+			text += ", have " + method.Actual
+		}
+
+		return text
+	case reasonAmbiguous:
+		paths := make([]string, len(method.Ambiguous))
+		for i, path := range method.Ambiguous {
+			paths[i] = strings.Join(append(append([]string{}, path...), method.Method), ".")
+		}
+
+		return fmt.Sprintf("%s, %s at depth %d",
+			method.Status, strings.Join(paths, " and "), method.Depth)
+	case reasonNameCase, reasonOtherPackage:
+		return fmt.Sprintf("%s, have %s", method.Status, method.Actual)
+	default:
+		return method.Status
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func explainModule(t *testing.T) *Finder {
+	t.Helper()
+
+	root := newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Service interface {
+	Start() error
+	Stop() error
+	Close() error
+}
+`,
+		"impl/impl.go": `package impl
+
+import "io"
+
+type Base struct{}
+
+func (*Base) Stop() error { return nil }
+
+type Logger struct{}
+
+func (Logger) Close() error { return nil }
+
+type Server struct {
+	Base
+	io.Closer
+}
+
+func (s *Server) Start() error { return nil }
+
+type Broken struct {
+	Logger
+	io.Closer
+}
+
+func (Broken) Start() error { return nil }
+func (Broken) Stop() int    { return 0 }
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Service")
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Service"}))
+
+	return finder
+}
+
+func TestFinder_ExplainType(t *testing.T) {
+	t.Parallel()
+
+	finder := explainModule(t)
+
+	explanation, err := finder.explainType("example.com/app/impl", "Server")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com/app/impl.Server", explanation.Type)
+	assert.Equal(t, "struct", explanation.Kind)
+	assert.Equal(t, "example.com/app/app.Service", explanation.Interface)
+	assert.True(t, explanation.Implements)
+	assert.False(t, explanation.ValueImplements)
+	assert.True(t, explanation.PointerImplements)
+
+	// Interface methods come sorted by name.
+	assert.Equal(t, []MethodExplanation{
+		{
+			Method:           "Close",
+			Expected:         "Close() error",
+			Status:           statusOK,
+			Actual:           "Close() error",
+			Receiver:         receiverInterface,
+			InValueMethodSet: true,
+			Via:              []string{"Closer"},
+			Depth:            1,
+			From:             "io.Closer",
+		},
+		{
+			Method:   "Start",
+			Expected: "Start() error",
+			Status:   statusOK,
+			Actual:   "Start() error",
+			Receiver: receiverPointer,
+			From:     "example.com/app/impl.Server",
+		},
+		{
+			Method:   "Stop",
+			Expected: "Stop() error",
+			Status:   statusOK,
+			Actual:   "Stop() error",
+			Receiver: receiverPointer,
+			Via:      []string{"Base"},
+			Depth:    1,
+			From:     "example.com/app/impl.Base",
+		},
+	}, explanation.Methods)
+}
+
+func TestFinder_ExplainTypeProblems(t *testing.T) {
+	t.Parallel()
+
+	finder := explainModule(t)
+
+	explanation, err := finder.explainType("example.com/app/impl", "Broken")
+	require.NoError(t, err)
+
+	assert.False(t, explanation.Implements)
+
+	byMethod := make(map[string]MethodExplanation)
+	for _, method := range explanation.Methods {
+		byMethod[method.Method] = method
+	}
+
+	assert.Equal(t, statusOK, byMethod["Start"].Status)
+	assert.Equal(t, receiverValue, byMethod["Start"].Receiver)
+	assert.True(t, byMethod["Start"].InValueMethodSet)
+
+	assert.Equal(t, reasonSignature, byMethod["Stop"].Status)
+	assert.Equal(t, "Stop() int", byMethod["Stop"].Actual)
+
+	assert.Equal(t, reasonAmbiguous, byMethod["Close"].Status)
+	assert.Equal(t, [][]string{{"Logger"}, {"Closer"}}, byMethod["Close"].Ambiguous)
+	assert.Equal(t, 1, byMethod["Close"].Depth)
+
+	var text bytes.Buffer
+	require.NoError(t, writeExplanation(&text, explanation, formatText))
+	assert.Equal(t, `example.com/app/impl.Broken (struct) does not implement example.com/app/app.Service
+  value: no, pointer: no
+  Close() error: ambiguous selector, Logger.Close and Closer.Close at depth 1
+  Start() error: ok, value receiver, declared directly
+  Stop() error: signature differs, value receiver, declared directly, have Stop() int
+`, text.String())
+
+	var jsonOutput bytes.Buffer
+	require.NoError(t, writeExplanation(&jsonOutput, explanation, formatJSON))
+
+	var decoded Explanation
+	require.NoError(t, json.Unmarshal(jsonOutput.Bytes(), &decoded))
+	assert.Equal(t, explanation, decoded)
+}
+
+func TestFinder_ExplainTypeErrors(t *testing.T) {
+	t.Parallel()
+
+	finder := explainModule(t)
+
+	_, err := finder.explainType("example.com/app/impl", "Missing")
+	require.ErrorIs(t, err, ErrTypeNotFound)
+
+	_, err = finder.explainType("example.com/app/nowhere", "Server")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -1,67 +1,192 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
 	"go/types"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
+// Implementation is one type that satisfies the target interface. Struct
+// holds the type's name whatever its kind; the key predates non-struct
+// results and is kept for compatibility.
 type Implementation struct {
-	Package     string `json:"package"`
-	Struct      string `json:"struct"`
-	PackagePath string `json:"packagePath"`
+	Package           string           `json:"package"`
+	Struct            string           `json:"struct"`
+	Kind              string           `json:"kind"`
+	PackagePath       string           `json:"packagePath"`
+	Module            string           `json:"module"`
+	Version           string           `json:"version,omitempty"`
+	ValueImplements   bool             `json:"valueImplements"`
+	PointerImplements bool             `json:"pointerImplements"`
+	TypeArgs          []string         `json:"typeArgs,omitempty"`
+	InterfaceTypeArgs []string         `json:"interfaceTypeArgs,omitempty"`
+	Missing           []MethodProblem  `json:"missing,omitempty"`
+	Mismatched        []MethodProblem  `json:"mismatched,omitempty"`
+	Platforms         []string         `json:"platforms,omitempty"`
+	InTest            bool             `json:"inTest,omitempty"`
+	TestPackage       string           `json:"testPackage,omitempty"`
+	Promoted          []PromotedMethod `json:"promoted,omitempty"`
+	Assertions        []Assertion      `json:"assertions,omitempty"`
+}
+
+// PromotedMethod is an interface method a type gets through struct
+// embedding rather than declaring it. Via lists the embedded fields it is
+// promoted through, outermost first; From is the type that declares it.
+type PromotedMethod struct {
+	Method string   `json:"method"`
+	Via    []string `json:"via"`
+	From   string   `json:"from"`
 }
 
 type Finder struct {
 	fset             *token.FileSet
 	interfaceName    string
-	interfaceMethods []string
+	typeArgSpecs     []string
+	target           *types.Named
+	iface            *types.Interface
+	ifaceGeneric     *types.Named
+	ifaceTypeArgs    []types.Type
 	modulePath       string
+	moduleRoot       string
+	goMod            *goModFile
+	workFile         string
+	modules          []workspaceModule
+	workReplaces     map[string]moduleVersion
+	nestedModules    bool
+	nestedRoots      map[string]bool
+	filter           *pathFilter
+	deps             bool
+	std              bool
+	depPackages      map[string]dependencyPackage
+	goroot           string
+	modCache         string
+	buildContext     build.Context
+	packages         map[string]*types.Package
+	secondary        map[string][]*types.Package
+	testPackages     map[string]testPackages
+	kinds            map[string]bool
+	valueOnly        bool
+	nearMiss         int
+	tests            bool
+	subject          *types.Named
+	interfaceMatches []InterfaceMatch
+	checkedPackages  map[*types.Package]bool
+	hierarchy        bool
+	hierarchyTypes   map[string]*types.Named
+	info             *types.Info
+	checkedFiles     map[*types.Package][]*ast.File
+	assertions       map[string][]assertedType
+	assertionSeen    map[string]bool
+	scanned          map[*types.Package]bool
 	results          []Implementation
 	config           *types.Config
 }
 
-type noopImporter struct{}
+func NewFinder(interfaceName string) *Finder {
+	// cgo files need the cgo tool to type-check; with cgo disabled the
+	// pure-Go fallbacks of the standard library are picked instead.
+	buildContext := build.Default
+	buildContext.CgoEnabled = false
 
-func (imp *noopImporter) Import(path string) (*types.Package, error) {
-	// Return a minimal package for any import
-	// This allows type checking to proceed without actually resolving imports
-	return types.NewPackage(path, path), nil
-}
+	finder := &Finder{
+		fset:          token.NewFileSet(),
+		interfaceName: interfaceName,
+		moduleRoot:    ".",
+		goroot:        buildContext.GOROOT,
+		modCache:      moduleCacheRoot(buildContext),
+		buildContext:  buildContext,
+		packages:      make(map[string]*types.Package),
+		secondary:     make(map[string][]*types.Package),
+		testPackages:  make(map[string]testPackages),
+		results:       make([]Implementation, 0),
+	}
 
-func NewFinder(interfaceName string) *Finder {
-	fset := token.NewFileSet()
-	config := &types.Config{
-		Importer: &noopImporter{},
+	finder.config = &types.Config{
+		Importer:         finder,
+		IgnoreFuncBodies: true,
+		FakeImportC:      true,
 		Error: func(_ error) {
 			// Ignore type checking errors for incomplete packages
 		},
 	}
 
-	return &Finder{
-		fset:          fset,
-		interfaceName: interfaceName,
-		results:       make([]Implementation, 0),
-		config:        config,
+	return finder
+}
+
+// moduleCacheRoot mirrors the go command's GOMODCACHE default: the first
+// GOPATH entry's pkg/mod directory.
+func moduleCacheRoot(buildContext build.Context) string {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return modCache
 	}
+
+	gopath := filepath.SplitList(buildContext.GOPATH)
+	if len(gopath) == 0 {
+		return ""
+	}
+
+	return filepath.Join(gopath[0], "pkg", "mod")
 }
 
+// validateGoModRoot finds the module root the way the go command does: the
+// nearest directory holding a go.mod, starting from the working directory
+// and walking up. Inside a go.work workspace the modules come from the
+// workspace instead.
 func (f *Finder) validateGoModRoot() error {
-	if _, err := os.Stat("./go.mod"); os.IsNotExist(err) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if workFile := findWorkFile(workDir); workFile != "" {
+		f.workFile = workFile
+
+		return nil
+	}
+
+	moduleRoot, ok := findModuleRoot(workDir)
+	if !ok {
 		return ErrGoModNotFound
 	}
 
+	f.moduleRoot = moduleRoot
+
 	return nil
 }
 
+// findModuleRoot returns dir or its closest parent that contains a go.mod.
+func findModuleRoot(dir string) (string, bool) {
+	dir = filepath.Clean(dir)
+
+	for {
+		if isFile(filepath.Join(dir, "go.mod")) {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
 func (f *Finder) loadModulePath() error {
-	content, err := os.ReadFile("./go.mod")
+	if f.workFile != "" {
+		return f.loadWorkspace()
+	}
+
+	content, err := os.ReadFile(filepath.Join(f.moduleRoot, "go.mod"))
 	if err != nil {
 		return fmt.Errorf(
 			"failed to read go.mod: %w",
@@ -69,17 +194,105 @@ func (f *Finder) loadModulePath() error {
 		)
 	}
 
-	lines := strings.SplitSeq(string(content), "\n")
-	for line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			f.modulePath = strings.TrimSpace(strings.TrimPrefix(line, "module"))
+	goMod, err := parseGoMod(string(content))
+	if err != nil {
+		return err
+	}
 
-			return nil
+	moduleRoot, err := filepath.Abs(f.moduleRoot)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to resolve module root: %w",
+			err,
+		)
+	}
+
+	f.goMod = goMod
+	f.modulePath = goMod.Module
+	f.moduleRoot = moduleRoot
+
+	return nil
+}
+
+// loadInterface resolves the target interface from spec: a file is parsed
+// directly, an import path goes through the importer (so stdlib and
+// dependency interfaces work offline), and "error" is the predeclared one.
+func (f *Finder) loadInterface(spec interfaceSpec) error {
+	f.typeArgSpecs = spec.TypeArgs
+
+	switch {
+	case spec.File != "":
+		return f.parseInterface(spec.File)
+	case spec.ImportPath != "":
+		return f.importInterface(spec.ImportPath)
+	default:
+		if spec.Name != builtinErrorSpec {
+			return fmt.Errorf("%w '%s'", ErrInterfaceNotFound, spec.Name)
 		}
+
+		errorType, _ := types.Universe.Lookup(builtinErrorSpec).Type().(*types.Named)
+
+		return f.setTarget(errorType)
+	}
+}
+
+// setInterface makes the interface of spec the target, dropping what a scan
+// for an earlier one found. Type-checked packages are kept, so scanning for
+// several interfaces in turn checks every package only once.
+func (f *Finder) setInterface(spec interfaceSpec) error {
+	f.interfaceName = spec.Name
+	f.results = make([]Implementation, 0)
+
+	if f.info != nil {
+		f.assertions = make(map[string][]assertedType)
+		f.assertionSeen = make(map[string]bool)
+		f.scanned = make(map[*types.Package]bool)
 	}
 
-	return ErrNoModuleDeclaration
+	return f.loadInterface(spec)
+}
+
+// importInterface loads the interface from the package at importPath. A
+// path starting with "." or "/" names a directory instead.
+func (f *Finder) importInterface(importPath string) error {
+	pkg, err := f.importPackage(importPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load interface package %s: %w",
+			importPath,
+			err,
+		)
+	}
+
+	named, ok := f.lookupInterface(pkg)
+	if !ok {
+		return fmt.Errorf("%w '%s' in %s",
+			ErrInterfaceNotFound, f.interfaceName, importPath)
+	}
+
+	if err := f.useInterface(named, nil); err != nil {
+		return fmt.Errorf("interface '%s' in %s: %w", f.interfaceName, importPath, err)
+	}
+
+	return nil
+}
+
+// importPackage loads a package by import path or, for "./"-style and
+// absolute paths, by directory.
+func (f *Finder) importPackage(importPath string) (*types.Package, error) {
+	if isDirectoryPath(importPath) {
+		if err := f.useEnclosingModule(importPath); err != nil {
+			return nil, err
+		}
+
+		return f.loadPackage(f.importPathForDir(importPath), importPath)
+	}
+
+	return f.Import(importPath)
+}
+
+func isDirectoryPath(path string) bool {
+	return strings.HasPrefix(path, ".") || filepath.IsAbs(path)
 }
 
 func (f *Finder) parseInterface(filePath string) error {
@@ -92,50 +305,241 @@ func (f *Finder) parseInterface(filePath string) error {
 		)
 	}
 
-	found := false
+	if err := f.useEnclosingModule(filepath.Dir(filePath)); err != nil {
+		return err
+	}
+
+	importPath := f.importPathForDir(filepath.Dir(filePath))
+
+	// A package checked already, by the scan for an earlier interface, is
+	// reused: checking it again would give it types distinct from those its
+	// importers were checked against.
+	pkg := f.packages[importPath]
+	if pkg == nil || strings.HasSuffix(filePath, "_test.go") {
+		pkg, err = f.checkInterfacePackage(filePath, file, importPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	named, ok := f.lookupInterface(pkg)
+	if !ok {
+		return fmt.Errorf("%w '%s' in %s",
+			ErrInterfaceNotFound, f.interfaceName, filePath)
+	}
+
+	if err := f.useInterface(named, f.embeddedNames(file)); err != nil {
+		return fmt.Errorf("interface '%s' in %s: %w", f.interfaceName, filePath, err)
+	}
+
+	return nil
+}
+
+// checkInterfacePackage type-checks the package of the interface file.
+func (f *Finder) checkInterfacePackage(
+	filePath string, file *ast.File, importPath string,
+) (*types.Package, error) {
+	// Sibling files are checked along with the interface file so that
+	// types declared elsewhere in the package resolve in method signatures.
+	files := []*ast.File{file}
+
+	siblings, err := f.parsePackageFiles(filepath.Dir(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sibling := range siblings {
+		if sibling.Name.Name != file.Name.Name ||
+			f.fset.File(sibling.Pos()).Name() == f.fset.File(file.Pos()).Name() {
+			continue
+		}
+
+		files = append(files, sibling)
+	}
+
+	pkg, err := f.typeCheckPackage(importPath, files)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the interface's package so that implementations importing it
+	// see the very same types in their method signatures.
+	if !strings.HasSuffix(filePath, "_test.go") {
+		f.packages[importPath] = pkg
+	}
+
+	return pkg, nil
+}
+
+// useInterface validates the embeds of the named interface and makes it the
+// target.
+func (f *Finder) useInterface(named *types.Named, embeddedNames []string) error {
+	iface, _ := named.Underlying().(*types.Interface)
+
+	if err := checkEmbeddedInterfaces(iface, embeddedNames); err != nil {
+		return err
+	}
+
+	return f.setTarget(named)
+}
+
+// embeddedNames returns the source form of each element embedded in the
+// target interface's declaration, in declaration order.
+func (f *Finder) embeddedNames(file *ast.File) []string {
+	var names []string
 
 	ast.Inspect(file, func(n ast.Node) bool {
-		if found {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != f.interfaceName {
+			return names == nil
+		}
+
+		iface, ok := ts.Type.(*ast.InterfaceType)
+		if !ok {
 			return false
 		}
 
-		if ts, ok := n.(*ast.TypeSpec); ok {
-			if ts.Name.Name == f.interfaceName {
-				if iface, ok := ts.Type.(*ast.InterfaceType); ok {
-					f.interfaceMethods = f.getInterfaceMethods(iface)
-					found = true
+		names = make([]string, 0, len(iface.Methods.List))
 
-					return false
-				}
+		for _, field := range iface.Methods.List {
+			if len(field.Names) == 0 {
+				names = append(names, types.ExprString(field.Type))
 			}
 		}
 
-		return true
+		return false
 	})
 
-	if !found {
-		return fmt.Errorf("%w '%s' in %s",
-			ErrInterfaceNotFound, f.interfaceName, filePath)
+	return names
+}
+
+// checkEmbeddedInterfaces makes sure every interface embedded in iface, at
+// any depth, was resolved. go/types flattens embedded interfaces (including
+// overlapping methods) into the method set, but an embed it could not resolve
+// contributes no methods at all, which would silently loosen the match.
+func checkEmbeddedInterfaces(iface *types.Interface, names []string) error {
+	for i := range iface.NumEmbeddeds() {
+		embedded := iface.EmbeddedType(i)
+		if embeddedResolved(embedded, make(map[*types.Interface]bool)) {
+			continue
+		}
+
+		name := types.TypeString(embedded, (*types.Package).Name)
+		if i < len(names) {
+			name = names[i]
+		}
+
+		return fmt.Errorf("%w: %s", ErrUnresolvedEmbed, name)
 	}
 
 	return nil
 }
 
-func (f *Finder) getInterfaceMethods(iface *ast.InterfaceType) []string {
-	var methods []string
+func embeddedResolved(typ types.Type, seen map[*types.Interface]bool) bool {
+	if basic, ok := typ.(*types.Basic); ok && basic.Kind() == types.Invalid {
+		return false
+	}
+
+	// Type terms such as ~int or unions are not interfaces and add no methods.
+	iface, ok := typ.Underlying().(*types.Interface)
+	if !ok || seen[iface] {
+		return true
+	}
+
+	seen[iface] = true
 
-	for _, method := range iface.Methods.List {
-		if len(method.Names) > 0 {
-			methods = append(methods, method.Names[0].Name)
+	for i := range iface.NumEmbeddeds() {
+		if !embeddedResolved(iface.EmbeddedType(i), seen) {
+			return false
 		}
 	}
 
+	return true
+}
+
+func (f *Finder) lookupInterface(pkg *types.Package) (*types.Named, bool) {
+	typeName, ok := pkg.Scope().Lookup(f.interfaceName).(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+
+	named, ok := typeName.Type().(*types.Named)
+	if !ok {
+		return nil, false
+	}
+
+	_, ok = named.Underlying().(*types.Interface)
+
+	return named, ok
+}
+
+// getInterfaceMethods renders the full method set of iface, one entry per
+// method in the "Name(params) results" form used in debug output.
+func (f *Finder) getInterfaceMethods(iface *types.Interface) []string {
+	methods := make([]string, 0, iface.NumMethods())
+
+	for method := range iface.Methods() {
+		methods = append(methods, methodString(method))
+	}
+
 	return methods
 }
 
+// methodString renders a method as "Name(params) results", qualifying types
+// by package name.
+func methodString(method *types.Func) string {
+	signature := types.TypeString(method.Type(), (*types.Package).Name)
+
+	return method.Name() + strings.TrimPrefix(signature, "func")
+}
+
+// scanDirectory scans searchDir and every package directory below it.
 func (f *Finder) scanDirectory(searchDir string) error {
+	return f.scanPackagePatterns([]packagePattern{{dir: searchDir, recursive: true}})
+}
+
+// scanPackagePatterns scans the directories the patterns cover, each one
+// once however many patterns match it.
+func (f *Finder) scanPackagePatterns(patterns []packagePattern) error {
+	analyzed := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		roots := []string{pattern.dir}
+		if pattern.recursive && pattern.match == nil {
+			roots = f.searchRoots(pattern.dir)
+		}
+
+		for _, root := range roots {
+			if err := f.walkDirectory(root, pattern, analyzed); err != nil {
+				return err
+			}
+		}
+	}
+
+	if f.std {
+		f.scanStd()
+	}
+
+	if f.deps {
+		f.scanDependencies()
+	}
+
+	f.applyAssertions()
+
+	return nil
+}
+
+func (f *Finder) walkDirectory(
+	searchDir string, pattern packagePattern, analyzed map[string]bool,
+) error {
 	slog.Debug("starting scan", "dir", searchDir)
 
+	// A search directory inside a nested module was asked for explicitly, so
+	// it is scanned as part of that module whatever -nested-modules says.
+	if err := f.useEnclosingModule(searchDir); err != nil {
+		return err
+	}
+
 	err := filepath.Walk(
 		searchDir,
 		func(
@@ -155,16 +559,49 @@ func (f *Finder) scanDirectory(searchDir string) error {
 				"name", info.Name(),
 			)
 
-			if !info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			if !info.IsDir() {
 				return nil
 			}
 
+			if path != searchDir && isIgnoredDir(info.Name()) {
+				slog.Debug("skipping ignored directory", "dir", path)
+
+				return filepath.SkipDir
+			}
+
 			if info.Name() == "vendor" || info.Name() == "node_modules" {
 				return filepath.SkipDir
 			}
 
-			slog.Debug("analyzing directory", "dir", path)
-			f.analyzeDirectory(path)
+			if reason := f.pathExclusion(path, true); reason != "" {
+				slog.Debug("skipping filtered directory", "dir", path, "reason", reason)
+
+				return filepath.SkipDir
+			}
+
+			if path != searchDir && f.isNestedModuleRoot(path) {
+				if !f.nestedModules {
+					slog.Debug("skipping nested module", "dir", path)
+
+					return filepath.SkipDir
+				}
+
+				if err := f.useEnclosingModule(path); err != nil {
+					return err
+				}
+			}
+
+			if f.workFile != "" && f.moduleForDir(path) == nil {
+				slog.Debug("skipping directory outside workspace modules", "dir", path)
+
+				return nil
+			}
+
+			f.analyzeMatchingDirectory(path, pattern, analyzed)
+
+			if !pattern.recursive {
+				return filepath.SkipDir
+			}
 
 			return nil
 		})
@@ -178,36 +615,194 @@ func (f *Finder) scanDirectory(searchDir string) error {
 	return nil
 }
 
+func (f *Finder) analyzeMatchingDirectory(
+	dirPath string, pattern packagePattern, analyzed map[string]bool,
+) {
+	if pattern.match != nil && !pattern.match(dirPath) {
+		return
+	}
+
+	absDir, err := filepath.Abs(dirPath)
+	if err != nil {
+		absDir = dirPath
+	}
+
+	if analyzed[absDir] {
+		return
+	}
+
+	analyzed[absDir] = true
+
+	f.analyzeDirectory(dirPath)
+}
+
 func (f *Finder) analyzeDirectory(dirPath string) {
 	slog.Debug("analyzing directory", "dir", dirPath)
 
-	files, err := f.parsePackageFiles(dirPath)
+	importPath := f.importPathForDir(dirPath)
+	start := len(f.results)
+
+	pkg, err := f.loadPackage(importPath, dirPath)
 	if err != nil {
-		slog.Debug("error parsing files", "dir", dirPath, "err", err)
+		slog.Debug("failed to load package", "dir", dirPath, "err", err)
+	} else {
+		slog.Debug("type-checked package", "package", pkg.Name(), "path", pkg.Path())
+		f.findImplementationsInTypedPackage(dirPath, pkg)
+	}
 
-		return
+	f.analyzeSecondaryPackages(dirPath, importPath)
+
+	if f.tests {
+		reported := make(map[string]bool)
+		for _, impl := range f.results[start:] {
+			reported[impl.Struct] = true
+		}
+
+		f.analyzeTestPackages(dirPath, importPath, pkg, reported)
 	}
+}
 
-	if len(files) == 0 {
-		slog.Debug("no files found", "dir", dirPath)
+// analyzeSecondaryPackages type-checks and scans, each on its own, the
+// packages of a directory other than the one its import path refers to, such
+// as a stray "package main" generator next to "package foo". Package clauses
+// are read first so single-package directories are not parsed twice.
+func (f *Finder) analyzeSecondaryPackages(dirPath, importPath string) {
+	packages, ok := f.secondary[dirPath]
+	if !ok {
+		packages = f.checkSecondaryPackages(dirPath, importPath)
+		f.secondary[dirPath] = packages
+	}
 
-		return
+	for _, pkg := range packages {
+		f.findImplementationsInTypedPackage(dirPath, pkg)
 	}
+}
 
-	slog.Debug("found files", "dir", dirPath, "count", len(files))
+// checkSecondaryPackages type-checks the secondary packages of a directory.
+// They are cached per directory, since nothing imports them.
+func (f *Finder) checkSecondaryPackages(dirPath, importPath string) []*types.Package {
+	clauses, err := f.parsePackageGroups(dirPath, parser.PackageClauseOnly)
+	if err != nil || len(clauses) < 2 {
+		return nil
+	}
 
-	pkg, err := f.typeCheckPackage(files)
+	groups, err := f.parsePackageGroups(dirPath, parser.ParseComments)
 	if err != nil {
-		slog.Debug("type check failed", "dir", dirPath, "err", err)
+		return nil
+	}
 
-		return
+	var packages []*types.Package
+
+	for _, group := range groups[1:] {
+		pkg, err := f.typeCheckPackage(importPath, group.files)
+		if err != nil {
+			slog.Debug("failed to type-check package",
+				"dir", dirPath, "package", group.name, "err", err)
+
+			continue
+		}
+
+		slog.Debug("type-checked secondary package", "package", pkg.Name(), "dir", dirPath)
+		packages = append(packages, pkg)
 	}
 
-	slog.Debug("type-checked package", "package", pkg.Name())
-	f.findImplementationsInTypedPackage(dirPath, pkg)
+	return packages
 }
 
+// importPathForDir derives the import path of the package in dirPath from its
+// location relative to the module root.
+func (f *Finder) importPathForDir(dirPath string) string {
+	if dep, ok := f.dependencyForDir(dirPath); ok {
+		return dep.ImportPath
+	}
+
+	modulePath, moduleRoot := f.modulePath, f.moduleRoot
+	if module := f.moduleForDir(dirPath); module != nil {
+		modulePath, moduleRoot = module.Path, module.Root
+	}
+
+	absRoot, err := filepath.Abs(moduleRoot)
+	if err != nil {
+		absRoot = moduleRoot
+	}
+
+	absDir, err := filepath.Abs(dirPath)
+	if err != nil {
+		absDir = dirPath
+	}
+
+	relPath, _ := filepath.Rel(absRoot, absDir)
+
+	return filepath.ToSlash(filepath.Join(modulePath, relPath))
+}
+
+// packageFiles is the files of a directory sharing one package clause.
+type packageFiles struct {
+	name  string
+	files []*ast.File
+}
+
+// parsePackageFiles parses the files of the package the directory's import
+// path refers to. Files with any other package clause are left out.
 func (f *Finder) parsePackageFiles(dirPath string) ([]*ast.File, error) {
+	groups, err := f.parsePackageGroups(dirPath, parser.ParseComments)
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+
+	return groups[0].files, nil
+}
+
+// parsePackageGroups parses the directory's non-test files and groups them by
+// package clause, primary package first. With parser.PackageClauseOnly it is
+// a cheap way to learn which packages a directory holds.
+func (f *Finder) parsePackageGroups(
+	dirPath string, mode parser.Mode,
+) ([]packageFiles, error) {
+	files, err := f.parseModuleFiles(dirPath, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupByPackage(dirPath, files), nil
+}
+
+// groupByPackage splits files by package clause. The first group is the
+// package the directory's import path refers to: the one named after the
+// directory, else the one with the most files, else the first by name.
+func groupByPackage(dirPath string, files []*ast.File) []packageFiles {
+	byName := make(map[string][]*ast.File)
+	for _, file := range files {
+		byName[file.Name.Name] = append(byName[file.Name.Name], file)
+	}
+
+	groups := make([]packageFiles, 0, len(byName))
+	for name, groupFiles := range byName {
+		groups = append(groups, packageFiles{name: name, files: groupFiles})
+	}
+
+	dirName := strings.ReplaceAll(filepath.Base(dirPath), "-", "_")
+
+	slices.SortFunc(groups, func(a, b packageFiles) int {
+		if (a.name == dirName) != (b.name == dirName) {
+			if a.name == dirName {
+				return -1
+			}
+
+			return 1
+		}
+
+		if len(a.files) != len(b.files) {
+			return cmp.Compare(len(b.files), len(a.files))
+		}
+
+		return cmp.Compare(a.name, b.name)
+	})
+
+	return groups
+}
+
+func (f *Finder) parseModuleFiles(dirPath string, mode parser.Mode) ([]*ast.File, error) {
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -217,6 +812,7 @@ func (f *Finder) parsePackageFiles(dirPath string) ([]*ast.File, error) {
 	}
 
 	files := make([]*ast.File, 0, len(entries))
+	buildContext := f.moduleBuildContext()
 
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") ||
@@ -224,9 +820,19 @@ func (f *Finder) parsePackageFiles(dirPath string) ([]*ast.File, error) {
 			continue
 		}
 
+		// File name suffixes and //go:build lines select files the same way
+		// go build does for the target platform and tags.
+		match, err := buildContext.MatchFile(dirPath, entry.Name())
+		if err != nil || !match {
+			slog.Debug("skipping file excluded by build constraints",
+				"dir", dirPath, "file", entry.Name(), "err", err)
+
+			continue
+		}
+
 		filePath := filepath.Join(dirPath, entry.Name())
 
-		file, err := parser.ParseFile(f.fset, filePath, nil, parser.ParseComments)
+		file, err := parser.ParseFile(f.fset, filePath, nil, mode)
 		if err != nil {
 			continue
 		}
@@ -237,14 +843,48 @@ func (f *Finder) parsePackageFiles(dirPath string) ([]*ast.File, error) {
 	return files, nil
 }
 
-func (f *Finder) typeCheckPackage(files []*ast.File) (*types.Package, error) {
+// moduleBuildContext is the build context for the scanned module's own files.
+// Dependencies are read with cgo disabled, but the module's cgo files are
+// kept whenever a native build would enable cgo: they are where its types
+// live, and FakeImportC lets them type-check.
+func (f *Finder) moduleBuildContext() build.Context {
+	buildContext := f.buildContext
+	buildContext.CgoEnabled = build.Default.CgoEnabled &&
+		buildContext.GOOS == build.Default.GOOS &&
+		buildContext.GOARCH == build.Default.GOARCH
+
+	return buildContext
+}
+
+// setBuildTarget selects the platform and build tags files are filtered for.
+// Empty goos or goarch keep the host's value. It must be called before any
+// package is loaded, as loaded packages are cached.
+func (f *Finder) setBuildTarget(goos, goarch string, tags []string) {
+	if goos != "" {
+		f.buildContext.GOOS = goos
+	}
+
+	if goarch != "" {
+		f.buildContext.GOARCH = goarch
+	}
+
+	f.buildContext.BuildTags = tags
+}
+
+func (f *Finder) typeCheckPackage(
+	importPath string, files []*ast.File,
+) (*types.Package, error) {
+	return f.typeCheckPackageWith(f.config, importPath, files)
+}
+
+func (f *Finder) typeCheckPackageWith(
+	config *types.Config, importPath string, files []*ast.File,
+) (*types.Package, error) {
 	if len(files) == 0 {
 		return nil, ErrNoFilesToTypeCheck
 	}
 
-	pkgName := files[0].Name.Name
-
-	pkg, err := f.config.Check(pkgName, f.fset, files, nil)
+	pkg, err := config.Check(importPath, f.fset, files, f.info)
 	if err != nil {
 		// Try to continue even if type checking fails
 		slog.Debug("type checking had errors, continuing", "err", err)
@@ -257,12 +897,22 @@ func (f *Finder) typeCheckPackage(files []*ast.File) (*types.Package, error) {
 		)
 	}
 
+	if f.checkedFiles != nil {
+		f.checkedFiles[pkg] = files
+	}
+
 	return pkg, nil
 }
 
 func (f *Finder) findImplementationsInTypedPackage(
 	dirPath string, pkg *types.Package,
 ) {
+	if f.subject != nil {
+		f.findInterfacesInTypedPackage(pkg)
+
+		return
+	}
+
 	scope := pkg.Scope()
 	for _, name := range scope.Names() {
 		obj := scope.Lookup(name)
@@ -272,35 +922,18 @@ func (f *Finder) findImplementationsInTypedPackage(
 
 		f.processTypeInScope(obj, dirPath, pkg)
 	}
+
+	f.collectAssertions(pkg)
 }
 
+// typeImplementsInterface reports whether a value of namedType or a pointer
+// to it can be assigned to the target interface. Method names, parameter and
+// result types, variadics and receiver kinds are all compared by go/types, so
+// the answer matches what the compiler means by "implements".
 func (f *Finder) typeImplementsInterface(namedType *types.Named) bool {
-	if len(f.interfaceMethods) == 0 {
-		return false
-	}
-
-	// Check both value and pointer method sets
-	valueMethodSet := types.NewMethodSet(namedType)
-	pointerMethodSet := types.NewMethodSet(types.NewPointer(namedType))
-
-	foundMethods := make(map[string]bool)
+	_, ok := f.matchType(namedType)
 
-	// Add methods from both sets
-	for method := range valueMethodSet.Methods() {
-		foundMethods[method.Obj().Name()] = true
-	}
-
-	for method := range pointerMethodSet.Methods() {
-		foundMethods[method.Obj().Name()] = true
-	}
-
-	for _, requiredMethod := range f.interfaceMethods {
-		if !foundMethods[requiredMethod] {
-			return false
-		}
-	}
-
-	return true
+	return ok
 }
 
 func (f *Finder) getResults() []Implementation {
@@ -105,93 +105,174 @@ func TestFinder_LoadModulePath(t *testing.T) {
 	}
 }
 
+func checkSource(t *testing.T, path, src string) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, 0)
+	require.NoError(t, err)
+
+	config := &types.Config{
+		Error: func(err error) {}, // Ignore errors
+	}
+
+	pkg, err := config.Check(path, fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	return pkg
+}
+
+func lookupNamed(t *testing.T, pkg *types.Package, name string) *types.Named {
+	t.Helper()
+
+	obj := pkg.Scope().Lookup(name)
+	require.NotNil(t, obj, "type %s not found", name)
+
+	typeName, ok := obj.(*types.TypeName)
+	require.True(t, ok, "%s is not a type name", name)
+
+	namedType, ok := typeName.Type().(*types.Named)
+	require.True(t, ok, "%s is not a named type", name)
+
+	return namedType
+}
+
+func lookupInterface(t *testing.T, pkg *types.Package, name string) *types.Interface {
+	t.Helper()
+
+	iface, ok := lookupNamed(t, pkg, name).Underlying().(*types.Interface)
+	require.True(t, ok, "%s is not an interface", name)
+
+	return iface
+}
+
 func TestFinder_GetInterfaceMethods(t *testing.T) {
 	t.Parallel()
 
 	finder := NewFinder("TestInterface")
 
-	// Create a simple interface AST
-	src := `
+	pkg := checkSource(t, "test", `
 package test
 
 type TestInterface interface {
 	Method1() error
 	Method2(string) int
-	Method3()
+	Method3(format string, args ...any)
 }
-`
-
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "test.go", src, 0)
-	require.NoError(t, err)
-
-	// Find the interface
-	var iface *ast.InterfaceType
-	ast.Inspect(file, func(n ast.Node) bool {
-		if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == "TestInterface" {
-			if interfaceType, ok := ts.Type.(*ast.InterfaceType); ok {
-				iface = interfaceType
-				return false
-			}
-		}
-		return true
-	})
-
-	require.NotNil(t, iface)
-
-	methods := finder.getInterfaceMethods(iface)
-	expected := []string{"Method1", "Method2", "Method3"}
+`)
 
-	require.Len(t, methods, len(expected))
-
-	for i, method := range methods {
-		assert.Equal(t, expected[i], method)
+	methods := finder.getInterfaceMethods(lookupInterface(t, pkg, "TestInterface"))
+	expected := []string{
+		"Method1() error",
+		"Method2(string) int",
+		"Method3(format string, args ...any)",
 	}
+
+	assert.Equal(t, expected, methods)
 }
 
 func TestFinder_TypeImplementsInterface(t *testing.T) {
 	t.Parallel()
 
-	finder := NewFinder("TestInterface")
-	finder.interfaceMethods = []string{"Start", "Stop", "GetName"}
-
-	// Create a test type that implements the interface
-	src := `
+	pkg := checkSource(t, "test", `
 package test
 
+type App interface {
+	Start() error
+	Stop() error
+	GetName() string
+}
+
+type WithMissing interface {
+	App
+	Missing()
+}
+
 type TestStruct struct{}
 
 func (t *TestStruct) Start() error { return nil }
 func (t *TestStruct) Stop() error { return nil }
 func (t *TestStruct) GetName() string { return "test" }
-`
+`)
 
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "test.go", src, 0)
-	require.NoError(t, err)
+	namedType := lookupNamed(t, pkg, "TestStruct")
 
-	config := &types.Config{
-		Error: func(err error) {}, // Ignore errors
-	}
+	finder := NewFinder("App")
+	finder.iface = lookupInterface(t, pkg, "App")
+	assert.True(t, finder.typeImplementsInterface(namedType))
 
-	pkg, err := config.Check("test", fset, []*ast.File{file}, nil)
-	require.NoError(t, err)
+	// Test with incomplete implementation
+	finder.iface = lookupInterface(t, pkg, "WithMissing")
+	assert.False(t, finder.typeImplementsInterface(namedType))
+}
 
-	// Find the TestStruct type
-	obj := pkg.Scope().Lookup("TestStruct")
-	require.NotNil(t, obj)
+func TestFinder_TypeImplementsInterfaceSignatures(t *testing.T) {
+	t.Parallel()
 
-	typeName, ok := obj.(*types.TypeName)
-	require.True(t, ok)
+	pkg := checkSource(t, "test", `
+package test
 
-	namedType, ok := typeName.Type().(*types.Named)
-	require.True(t, ok)
+type Context interface{ Done() <-chan struct{} }
 
-	assert.True(t, finder.typeImplementsInterface(namedType))
+type Service interface {
+	Start() error
+	Log(format string, args ...any)
+	Get(ctx Context, key string) ([]byte, bool)
+}
 
-	// Test with incomplete implementation
-	finder.interfaceMethods = []string{"Start", "Stop", "GetName", "Missing"}
-	assert.False(t, finder.typeImplementsInterface(namedType))
+type Exact struct{}
+
+func (Exact) Start() error                                   { return nil }
+func (Exact) Log(format string, args ...any)                 {}
+func (Exact) Get(ctx Context, key string) ([]byte, bool)     { return nil, false }
+
+type PointerReceivers struct{}
+
+func (*PointerReceivers) Start() error                               { return nil }
+func (*PointerReceivers) Log(format string, args ...any)             {}
+func (*PointerReceivers) Get(ctx Context, key string) ([]byte, bool) { return nil, false }
+
+type WrongParams struct{ Exact }
+
+func (WrongParams) Start(ctx Context) error { return nil }
+
+type WrongResults struct{ Exact }
+
+func (WrongResults) Start() int { return 0 }
+
+type NotVariadic struct{ Exact }
+
+func (NotVariadic) Log(format string, args []any) {}
+
+type WrongArgType struct{ Exact }
+
+func (WrongArgType) Get(ctx Context, key int) ([]byte, bool) { return nil, false }
+`)
+
+	finder := NewFinder("Service")
+	finder.iface = lookupInterface(t, pkg, "Service")
+
+	testCases := []struct {
+		name     string
+		typeName string
+		expected bool
+	}{
+		{name: "exact signatures", typeName: "Exact", expected: true},
+		{name: "pointer receivers", typeName: "PointerReceivers", expected: true},
+		{name: "extra parameter", typeName: "WrongParams", expected: false},
+		{name: "different result", typeName: "WrongResults", expected: false},
+		{name: "slice instead of variadic", typeName: "NotVariadic", expected: false},
+		{name: "different parameter type", typeName: "WrongArgType", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			namedType := lookupNamed(t, pkg, tc.typeName)
+			assert.Equal(t, tc.expected, finder.typeImplementsInterface(namedType))
+		})
+	}
 }
 
 func TestFinder_GetResults(t *testing.T) {
@@ -242,20 +323,22 @@ func TestFinder_LoadModulePathErrors(t *testing.T) {
 }
 
 func TestFinder_ParseInterfaceErrors(t *testing.T) {
-	// not parallel: subtests share a finder whose fset/interfaceMethods are mutated by parseInterface
+	// not parallel: subtests share a finder whose fset/iface are mutated by parseInterface
 
 	finder := NewFinder("TestInterface")
-	tempDir := t.TempDir()
 
 	testCases := []struct {
-		name        string
-		fileContent string
-		expectError bool
+		name            string
+		fileContent     string
+		extraFiles      map[string]string
+		expectedMethods []string
+		expectError     bool
 	}{
 		{
-			name:        "valid interface",
-			fileContent: "package test\ntype TestInterface interface { Method() }",
-			expectError: false,
+			name:            "valid interface",
+			fileContent:     "package test\ntype TestInterface interface { Method() }",
+			expectedMethods: []string{"Method()"},
+			expectError:     false,
 		},
 		{
 			name:        "invalid Go syntax",
@@ -267,6 +350,14 @@ func TestFinder_ParseInterfaceErrors(t *testing.T) {
 			fileContent: "package test\ntype OtherInterface interface { Method() }",
 			expectError: true,
 		},
+		{
+			name:        "interface using sibling file types",
+			fileContent: "package test\ntype TestInterface interface { Configure(Config) error }",
+			extraFiles: map[string]string{
+				"config.go": "package test\ntype Config struct{ Name string }",
+			},
+			expectedMethods: []string{"Configure(test.Config) error"},
+		},
 		{
 			name:        "type is not interface",
 			fileContent: "package test\ntype TestInterface struct { Field int }",
@@ -276,9 +367,14 @@ func TestFinder_ParseInterfaceErrors(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			testFile := filepath.Join(tempDir, "test.go")
+			caseDir := t.TempDir()
+			testFile := filepath.Join(caseDir, "test.go")
 			require.NoError(t, os.WriteFile(testFile, []byte(tc.fileContent), 0o644))
 
+			for name, content := range tc.extraFiles {
+				require.NoError(t, os.WriteFile(filepath.Join(caseDir, name), []byte(content), 0o644))
+			}
+
 			err := finder.parseInterface(testFile)
 
 			if tc.expectError {
@@ -287,6 +383,7 @@ func TestFinder_ParseInterfaceErrors(t *testing.T) {
 			}
 
 			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMethods, finder.getInterfaceMethods(finder.iface))
 		})
 	}
 }
@@ -343,7 +440,7 @@ func TestFinder_TypeCheckPackageErrors(t *testing.T) {
 	finder := NewFinder("TestInterface")
 
 	// Test with empty file slice
-	pkg, err := finder.typeCheckPackage([]*ast.File{})
+	pkg, err := finder.typeCheckPackage("test", []*ast.File{})
 	assert.Nil(t, pkg)
 	require.ErrorIs(t, err, ErrNoFilesToTypeCheck)
 }
@@ -351,32 +448,20 @@ func TestFinder_TypeCheckPackageErrors(t *testing.T) {
 func TestFinder_TypeImplementsInterfaceEdgeCases(t *testing.T) {
 	t.Parallel()
 
-	finder := NewFinder("TestInterface")
-
-	// Test with empty interface methods
-	finder.interfaceMethods = []string{}
-
-	src := `package test
+	pkg := checkSource(t, "test", `package test
+type Empty interface{}
 type TestStruct struct{}
 func (t *TestStruct) Method() {}
-`
-
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "test.go", src, 0)
-	require.NoError(t, err)
-
-	config := &types.Config{Error: func(err error) {}}
-	pkg, err := config.Check("test", fset, []*ast.File{file}, nil)
-	require.NoError(t, err)
+`)
 
-	obj := pkg.Scope().Lookup("TestStruct")
-	typeName, ok := obj.(*types.TypeName)
-	require.True(t, ok)
+	finder := NewFinder("Empty")
 
-	namedType, ok := typeName.Type().(*types.Named)
-	require.True(t, ok)
+	// Should return false before an interface has been parsed
+	namedType := lookupNamed(t, pkg, "TestStruct")
+	assert.False(t, finder.typeImplementsInterface(namedType))
 
-	// Should return false for empty interface methods
+	// Should return false for an interface without methods
+	finder.iface = lookupInterface(t, pkg, "Empty")
 	assert.False(t, finder.typeImplementsInterface(namedType))
 }
 
@@ -400,3 +485,578 @@ func TestFinder_AnalyzeDirectoryErrorPaths(t *testing.T) {
 	// Should not panic and continue execution
 	assert.Empty(t, finder.results)
 }
+
+func TestFinder_EmbeddedInterfaces(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"base/base.go": `package base
+
+type Named interface {
+	Name() string
+}
+
+type Closer interface {
+	Close() error
+}
+`,
+		"iface/iface.go": `package iface
+
+import (
+	"io"
+
+	"example.com/app/base"
+)
+
+// ReadWriteCloser embeds stdlib and local interfaces.
+type ReadWriteCloser interface {
+	io.Reader
+	io.Writer
+	Closer
+}
+
+type Closer interface {
+	Close() error
+}
+
+// ReadCloser overlaps: both embeds declare Close() error.
+type ReadCloser interface {
+	io.ReadCloser
+	io.Closer
+}
+
+// NamedCloser embeds interfaces from another package of the module.
+type NamedCloser interface {
+	base.Named
+	base.Closer
+}
+
+// Nested embeds an interface that itself embeds others.
+type Nested interface {
+	ReadWriteCloser
+	base.Named
+}
+`,
+		"impl/impl.go": `package impl
+
+type Full struct{}
+
+func (Full) Read(p []byte) (int, error)  { return 0, nil }
+func (Full) Write(p []byte) (int, error) { return 0, nil }
+func (Full) Close() error                { return nil }
+func (Full) Name() string                { return "" }
+
+type ReaderCloser struct{}
+
+func (ReaderCloser) Read(p []byte) (int, error) { return 0, nil }
+func (ReaderCloser) Close() error               { return nil }
+
+type NamedOnly struct{}
+
+func (NamedOnly) Name() string { return "" }
+func (NamedOnly) Close() error { return nil }
+`,
+	})
+
+	testCases := []struct {
+		name     string
+		iface    string
+		expected []string
+	}{
+		{name: "stdlib and local embeds", iface: "ReadWriteCloser", expected: []string{"Full"}},
+		{name: "overlapping methods", iface: "ReadCloser", expected: []string{"Full", "ReaderCloser"}},
+		{name: "module package embeds", iface: "NamedCloser", expected: []string{"Full", "NamedOnly"}},
+		{name: "nested embeds", iface: "Nested", expected: []string{"Full"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, tc.iface)
+			require.NoError(t, finder.parseInterface(filepath.Join(root, "iface", "iface.go")))
+			require.NoError(t, finder.scanDirectory(filepath.Join(root, "impl")))
+
+			found := make([]string, 0, len(finder.getResults()))
+			for _, result := range finder.getResults() {
+				found = append(found, result.Struct)
+			}
+
+			assert.ElementsMatch(t, tc.expected, found)
+		})
+	}
+}
+
+func TestFinder_UnresolvedEmbeddedInterface(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"iface/iface.go": `package iface
+
+import "example.org/missing"
+
+type Broken interface {
+	missing.Iface
+	Close() error
+}
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Broken")
+	err := finder.parseInterface(filepath.Join(root, "iface", "iface.go"))
+	require.ErrorIs(t, err, ErrUnresolvedEmbed)
+	assert.Contains(t, err.Error(), "missing.Iface")
+}
+
+func TestFinder_LoadInterface(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"store/store.go": `package store
+
+type Store interface {
+	Get(id string) ([]byte, error)
+}
+`,
+		"impl/impl.go": `package impl
+
+import "errors"
+
+type Writer struct{}
+
+func (*Writer) Write(p []byte) (int, error) { return len(p), nil }
+
+type NotFound struct{}
+
+func (NotFound) Error() string { return errors.New("not found").Error() }
+
+type Memory struct{}
+
+func (Memory) Get(id string) ([]byte, error) { return nil, nil }
+`,
+	})
+
+	testCases := []struct {
+		name        string
+		spec        interfaceSpec
+		expected    []string
+		expectError error
+	}{
+		{
+			name:     "stdlib import path",
+			spec:     interfaceSpec{ImportPath: "io", Name: "Writer"},
+			expected: []string{"Writer"},
+		},
+		{
+			name:     "module import path",
+			spec:     interfaceSpec{ImportPath: "example.com/app/store", Name: "Store"},
+			expected: []string{"Memory"},
+		},
+		{
+			name:     "directory path",
+			spec:     interfaceSpec{ImportPath: filepath.Join(root, "store"), Name: "Store"},
+			expected: []string{"Memory"},
+		},
+		{
+			name:     "builtin error",
+			spec:     interfaceSpec{Name: "error"},
+			expected: []string{"NotFound"},
+		},
+		{
+			name:        "unknown builtin",
+			spec:        interfaceSpec{Name: "comparable"},
+			expectError: ErrInterfaceNotFound,
+		},
+		{
+			name:        "missing interface in package",
+			spec:        interfaceSpec{ImportPath: "io", Name: "Nope"},
+			expectError: ErrInterfaceNotFound,
+		},
+		{
+			name:        "unresolvable package",
+			spec:        interfaceSpec{ImportPath: "example.org/missing", Name: "Store"},
+			expectError: ErrImportNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, tc.spec.Name)
+			err := finder.loadInterface(tc.spec)
+
+			if tc.expectError != nil {
+				require.ErrorIs(t, err, tc.expectError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, finder.scanDirectory(filepath.Join(root, "impl")))
+
+			found := make([]string, 0, len(finder.getResults()))
+			for _, result := range finder.getResults() {
+				found = append(found, result.Struct)
+			}
+
+			assert.ElementsMatch(t, tc.expected, found)
+		})
+	}
+}
+
+func TestFinder_MultiplePackagesInDirectory(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"store/store.go": `package store
+
+type Closer interface {
+	Close() error
+}
+
+type File struct{}
+
+func (File) Close() error { return nil }
+`,
+		"store/gen.go": `package main
+
+import "example.com/app/store"
+
+type generator struct{}
+
+func (generator) Close() error { return nil }
+
+var _ store.Closer = generator{}
+
+func main() {}
+`,
+		"store/gen_ignored.go": `//go:build ignore
+
+package main
+
+type ignored struct{}
+
+func (ignored) Close() error { return nil }
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Closer")
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/store", Name: "Closer"}))
+	require.NoError(t, finder.scanDirectory(filepath.Join(root, "store")))
+
+	packages := make(map[string]string)
+	for _, result := range finder.getResults() {
+		packages[result.Struct] = result.Package
+	}
+
+	assert.Equal(t, map[string]string{"File": "store", "generator": "main"}, packages)
+}
+
+func TestGroupByPackage(t *testing.T) {
+	t.Parallel()
+
+	newFile := func(name string) *ast.File {
+		return &ast.File{Name: ast.NewIdent(name)}
+	}
+
+	testCases := []struct {
+		name     string
+		dir      string
+		packages []string
+		expected []string
+	}{
+		{
+			name:     "named after directory",
+			dir:      "/src/store",
+			packages: []string{"main", "main", "store"},
+			expected: []string{"store", "main"},
+		},
+		{
+			name:     "hyphenated directory",
+			dir:      "/src/go-store",
+			packages: []string{"main", "go_store"},
+			expected: []string{"go_store", "main"},
+		},
+		{
+			name:     "most files",
+			dir:      "/src/cmd",
+			packages: []string{"tool", "main", "main"},
+			expected: []string{"main", "tool"},
+		},
+		{
+			name:     "tie broken by name",
+			dir:      "/src/x",
+			packages: []string{"zeta", "alpha"},
+			expected: []string{"alpha", "zeta"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := make([]*ast.File, len(tc.packages))
+			for i, name := range tc.packages {
+				files[i] = newFile(name)
+			}
+
+			groups := groupByPackage(tc.dir, files)
+
+			names := make([]string, len(groups))
+			for i, group := range groups {
+				names[i] = group.name
+			}
+
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestFindModuleRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":                "module example.com/app\n",
+		"internal/pkg/x.go":     "package pkg\n",
+		"tools/go.mod":          "module example.com/app/tools\n",
+		"tools/cmd/gen/main.go": "package main\n",
+	})
+
+	testCases := []struct {
+		name     string
+		dir      string
+		expected string
+	}{
+		{name: "root", dir: root, expected: root},
+		{name: "subdirectory", dir: filepath.Join(root, "internal", "pkg"), expected: root},
+		{name: "nested module", dir: filepath.Join(root, "tools", "cmd", "gen"), expected: filepath.Join(root, "tools")},
+		{name: "unclean path", dir: filepath.Join(root, "internal") + "/../internal/pkg/", expected: root},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			moduleRoot, ok := findModuleRoot(tc.dir)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, moduleRoot)
+		})
+	}
+}
+
+func TestFinder_ModuleFromSubdirectory(t *testing.T) {
+	// not parallel: calls os.Chdir, mutates process cwd
+	root := newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Server interface{ Start() error }
+`,
+		"impl/web/web.go": `package web
+
+type Web struct{}
+
+func (*Web) Start() error { return nil }
+`,
+	})
+
+	oldDir, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(oldDir))
+	})
+
+	require.NoError(t, os.Chdir(filepath.Join(root, "impl")))
+
+	finder := NewFinder("Server")
+	require.NoError(t, finder.validateGoModRoot())
+	require.NoError(t, finder.loadModulePath())
+
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	require.NoError(t, err)
+
+	moduleRoot, err := filepath.EvalSymlinks(finder.moduleRoot)
+	require.NoError(t, err)
+	assert.Equal(t, resolvedRoot, moduleRoot)
+	assert.Equal(t, "example.com/app", finder.modulePath)
+
+	require.NoError(t, finder.loadInterface(interfaceSpec{File: "../app/app.go", Name: "Server"}))
+	require.NoError(t, finder.scanDirectory("."))
+
+	results := finder.getResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, "Web", results[0].Struct)
+	assert.Equal(t, "example.com/app/impl/web", results[0].PackagePath)
+}
+
+func TestFinder_NestedModules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		nested bool
+		dir    string
+		want   map[string]string
+	}{
+		{
+			name: "stops at nested modules",
+			dir:  ".",
+			want: map[string]string{
+				"example.com/app/impl.Impl": "example.com/app",
+			},
+		},
+		{
+			name:   "descends into nested modules",
+			nested: true,
+			dir:    ".",
+			want: map[string]string{
+				"example.com/app/impl.Impl": "example.com/app",
+				"example.com/tools/cmd.Cmd": "example.com/tools",
+			},
+		},
+		{
+			name: "search directory inside a nested module",
+			dir:  "tools/cmd",
+			want: map[string]string{
+				"example.com/tools/cmd.Cmd": "example.com/tools",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := newTempModule(t, map[string]string{
+				"app/app.go": `package app
+
+type Server interface{ Start() error }
+`,
+				"impl/impl.go": `package impl
+
+type Impl struct{}
+
+func (Impl) Start() error { return nil }
+`,
+				"tools/go.mod": "module example.com/tools\n\ngo 1.24\n",
+				"tools/cmd/cmd.go": `package cmd
+
+type Cmd struct{}
+
+func (*Cmd) Start() error { return nil }
+`,
+			})
+
+			finder := newModuleFinder(t, root, "Server")
+			finder.nestedModules = tc.nested
+
+			require.NoError(t, finder.loadInterface(interfaceSpec{
+				File: filepath.Join(root, "app", "app.go"),
+				Name: "Server",
+			}))
+			require.NoError(t, finder.scanDirectory(filepath.Join(root, tc.dir)))
+
+			got := make(map[string]string)
+			for _, impl := range finder.getResults() {
+				got[impl.PackagePath+"."+impl.Struct] = impl.Module
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFinder_InterfaceInNestedModule(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		nested bool
+		dir    string
+		want   map[string]string
+	}{
+		{
+			name: "search directory is the nested module",
+			dir:  "nested",
+			want: map[string]string{"example.com/inner/impl.S": "example.com/inner"},
+		},
+		{
+			name:   "descends into the nested module",
+			nested: true,
+			dir:    ".",
+			want:   map[string]string{"example.com/inner/impl.S": "example.com/inner"},
+		},
+		{
+			name: "stops at the nested module",
+			dir:  ".",
+			want: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			writeTree(t, root, map[string]string{
+				"go.mod":        "module example.com/outer\n\ngo 1.24\n",
+				"nested/go.mod": "module example.com/inner\n\ngo 1.24\n",
+				"nested/app/app.go": `package app
+
+type Config struct{}
+
+type Svc interface{ Serve(c Config) error }
+`,
+				"nested/impl/impl.go": `package impl
+
+import "example.com/inner/app"
+
+type S struct{}
+
+func (S) Serve(c app.Config) error { return nil }
+`,
+			})
+
+			finder := newModuleFinder(t, root, "Svc")
+			finder.nestedModules = tc.nested
+
+			// The interface file is checked under the nested module's path,
+			// so the implementation's app.Config is the interface's.
+			require.NoError(t, finder.loadInterface(interfaceSpec{
+				File: filepath.Join(root, "nested", "app", "app.go"),
+				Name: "Svc",
+			}))
+			assert.Equal(t, "example.com/inner/app", finder.target.Obj().Pkg().Path())
+
+			require.NoError(t, finder.scanDirectory(filepath.Join(root, tc.dir)))
+
+			got := make(map[string]string)
+			for _, impl := range finder.getResults() {
+				got[impl.PackagePath+"."+impl.Struct] = impl.Module
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFinder_ImportPackageInNestedModule(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":        "module example.com/outer\n\ngo 1.24\n",
+		"nested/go.mod": "module example.com/inner\n\ngo 1.24\n",
+		"nested/impl/impl.go": `package impl
+
+type S struct{}
+`,
+	})
+
+	finder := newModuleFinder(t, root, "")
+
+	pkg, err := finder.importPackage(filepath.Join(root, "nested", "impl"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/inner/impl", pkg.Path())
+}
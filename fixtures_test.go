@@ -36,12 +36,8 @@ func TestWithFixtures(t *testing.T) {
 	require.NoError(t, finder.parseInterface("internal/app/app.go"))
 
 	// Verify interface methods were parsed correctly
-	expectedMethods := []string{"Start", "Stop", "GetName"}
-	require.Len(t, finder.interfaceMethods, len(expectedMethods))
-
-	for i, method := range expectedMethods {
-		assert.Equal(t, method, finder.interfaceMethods[i])
-	}
+	expectedMethods := []string{"GetName() string", "Start() error", "Stop() error"}
+	assert.Equal(t, expectedMethods, finder.getInterfaceMethods(finder.iface))
 
 	require.NoError(t, finder.scanDirectory("pkg/"))
 
@@ -87,11 +83,11 @@ func TestParseInterfaceSpecWithFixtures(t *testing.T) {
 
 	// Test with fixtures path
 	spec := interfaceFile + ":App"
-	file, name, err := parseInterfaceSpec(spec)
+	parsed, err := parseInterfaceSpec(spec)
 
 	require.NoError(t, err)
-	assert.Equal(t, interfaceFile, file)
-	assert.Equal(t, "App", name)
+	assert.Equal(t, interfaceFile, parsed.File)
+	assert.Equal(t, "App", parsed.Name)
 }
 
 func TestValidateArgsWithFixtures(t *testing.T) {
@@ -105,7 +101,7 @@ func TestValidateArgsWithFixtures(t *testing.T) {
 	searchDir := filepath.Join(fixturesDir, "pkg")
 
 	// This should not panic since files exist
-	validateArgs(interfaceFile, "App", searchDir) //nolint:errcheck // exercising panic-free call path only
+	validateArgs(interfaceSpec{File: interfaceFile, Name: "App"}, searchDir) //nolint:errcheck // exercising panic-free call path only
 
 	// Test with non-existent file - this will call log.Fatal but we can't
 	// easily test that
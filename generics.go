@@ -0,0 +1,479 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// wildcardTypeArg in an interface spec matches any type argument.
+const wildcardTypeArg = "*"
+
+// qualifiedTypePattern matches "importpath.Name" references inside a type
+// argument, e.g. "time.Time" or "example.com/app/model.User".
+var qualifiedTypePattern = regexp.MustCompile(
+	`[A-Za-z0-9_.~/-]+\.[A-Za-z_][A-Za-z0-9_]*`,
+)
+
+// typeMatch records how a candidate satisfies the target: through its value
+// or only through a pointer, and under which instantiation — typeArgs for a
+// generic candidate, interfaceTypeArgs for a generic target queried with
+// wildcards.
+type typeMatch struct {
+	valueImplements   bool
+	pointerImplements bool
+	typeArgs          []types.Type
+	interfaceTypeArgs []types.Type
+}
+
+// setTarget makes named the target interface, applying the spec's type
+// arguments. A generic interface with wildcard (or omitted) type arguments is
+// kept uninstantiated so each candidate can be matched under its own
+// instantiation.
+func (f *Finder) setTarget(named *types.Named) error {
+	f.target = named
+	iface, _ := named.Underlying().(*types.Interface)
+	tparams := named.TypeParams()
+
+	if tparams.Len() == 0 {
+		if len(f.typeArgSpecs) > 0 {
+			return fmt.Errorf("%w: %s is not generic", ErrTypeArgCount, named.Obj().Name())
+		}
+
+		f.iface = iface
+
+		return nil
+	}
+
+	typeArgSpecs := f.typeArgSpecs
+	if len(typeArgSpecs) == 0 {
+		typeArgSpecs = make([]string, tparams.Len())
+		for i := range typeArgSpecs {
+			typeArgSpecs[i] = wildcardTypeArg
+		}
+	}
+
+	if len(typeArgSpecs) != tparams.Len() {
+		return fmt.Errorf("%w: %s has %d type parameters, got %d",
+			ErrTypeArgCount, named.Obj().Name(), tparams.Len(), len(typeArgSpecs))
+	}
+
+	typeArgs, wildcard, err := f.evalTypeArgs(named.Obj().Pkg(), typeArgSpecs)
+	if err != nil {
+		return err
+	}
+
+	if wildcard {
+		f.iface = iface
+		f.ifaceGeneric = named
+		f.ifaceTypeArgs = typeArgs
+
+		return nil
+	}
+
+	instance, err := types.Instantiate(nil, named, typeArgs, true)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTypeArg, err)
+	}
+
+	f.iface, _ = instance.Underlying().(*types.Interface)
+
+	return nil
+}
+
+// evalTypeArgs evaluates each type argument; wildcards are left nil.
+func (f *Finder) evalTypeArgs(
+	pkg *types.Package, specs []string,
+) ([]types.Type, bool, error) {
+	typeArgs := make([]types.Type, len(specs))
+	wildcard := false
+
+	for i, spec := range specs {
+		if spec == wildcardTypeArg {
+			wildcard = true
+
+			continue
+		}
+
+		typeArg, err := f.evalTypeArg(pkg, spec)
+		if err != nil {
+			return nil, false, err
+		}
+
+		typeArgs[i] = typeArg
+	}
+
+	return typeArgs, wildcard, nil
+}
+
+// evalTypeArg evaluates a type expression. Unqualified names resolve in the
+// interface's package, "importpath.Name" references go through the importer.
+func (f *Finder) evalTypeArg(pkg *types.Package, spec string) (types.Type, error) {
+	evalPkg := types.NewPackage("gofindimpl/typeargs", "typeargs")
+	scope := evalPkg.Scope()
+
+	if pkg != nil {
+		for _, name := range pkg.Scope().Names() {
+			scope.Insert(pkg.Scope().Lookup(name))
+		}
+	}
+
+	aliases := make(map[string]string)
+
+	var importErr error
+
+	expr := qualifiedTypePattern.ReplaceAllStringFunc(spec, func(ref string) string {
+		dot := strings.LastIndex(ref, ".")
+		path, name := ref[:dot], ref[dot+1:]
+
+		alias, ok := aliases[path]
+		if !ok {
+			imported, err := f.Import(path)
+			if err != nil {
+				importErr = err
+
+				return ref
+			}
+
+			alias = fmt.Sprintf("_p%d", len(aliases))
+			aliases[path] = alias
+			scope.Insert(types.NewPkgName(token.NoPos, evalPkg, alias, imported))
+		}
+
+		return alias + "." + name
+	})
+
+	if importErr != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidTypeArg, spec, importErr)
+	}
+
+	tv, err := types.Eval(f.fset, evalPkg, token.NoPos, expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidTypeArg, spec, err)
+	}
+
+	if !tv.IsType() {
+		return nil, fmt.Errorf("%w %s: not a type", ErrInvalidTypeArg, spec)
+	}
+
+	return tv.Type, nil
+}
+
+// matchType reports whether namedType, or a pointer to it, implements the
+// target and under which instantiation.
+func (f *Finder) matchType(namedType *types.Named) (typeMatch, bool) {
+	if f.iface == nil || f.iface.Empty() {
+		return typeMatch{}, false
+	}
+
+	if namedType.TypeParams().Len() == 0 && f.ifaceGeneric == nil {
+		match := methodSetMatch(namedType, f.iface)
+
+		return match, match.implements()
+	}
+
+	return f.matchGeneric(namedType)
+}
+
+// matchGeneric infers type arguments for a generic candidate and for the
+// target's wildcard type parameters by unifying the target's method
+// signatures with the candidate's, then confirms the inferred instantiation
+// with go/types. Candidate type parameters that no method pins down stay as
+// they are, meaning "for any type argument".
+func (f *Finder) matchGeneric(namedType *types.Named) (typeMatch, bool) {
+	candidateParams := typeParamList(namedType.TypeParams())
+	targetParams := f.targetTypeParams()
+
+	candidate, ok := selfInstance(namedType, candidateParams)
+	if !ok {
+		return typeMatch{}, false
+	}
+
+	u := f.newMatchUnifier(candidateParams, targetParams)
+
+	for method := range f.iface.Methods() {
+		obj, _, _ := types.LookupFieldOrMethod(candidate, true, method.Pkg(), method.Name())
+
+		fn, ok := obj.(*types.Func)
+		if !ok || !u.unify(method.Type(), fn.Type()) {
+			return typeMatch{}, false
+		}
+	}
+
+	return f.confirmInstantiation(namedType, u, candidateParams, targetParams)
+}
+
+// targetTypeParams returns the type parameters of a generic target queried
+// with wildcards, which are inferred per candidate.
+func (f *Finder) targetTypeParams() []*types.TypeParam {
+	if f.ifaceGeneric == nil {
+		return nil
+	}
+
+	return typeParamList(f.ifaceGeneric.TypeParams())
+}
+
+// newMatchUnifier returns a unifier over the candidate's and the target's
+// type parameters, with the target's concrete type arguments already bound.
+func (f *Finder) newMatchUnifier(
+	candidateParams, targetParams []*types.TypeParam,
+) *unifier {
+	u := newUnifier(append(append([]*types.TypeParam{}, candidateParams...), targetParams...))
+
+	for i, typeArg := range f.ifaceTypeArgs {
+		if typeArg != nil {
+			u.bindings[targetParams[i]] = typeArg
+		}
+	}
+
+	return u
+}
+
+// selfInstance instantiates a generic type with its own type parameters, so
+// its methods can be looked up; non-generic types are returned as they are.
+func selfInstance(
+	namedType *types.Named, params []*types.TypeParam,
+) (types.Type, bool) {
+	if len(params) == 0 {
+		return namedType, true
+	}
+
+	self, err := types.Instantiate(nil, namedType, typesOf(params), false)
+	if err != nil {
+		return nil, false
+	}
+
+	return self, true
+}
+
+func (f *Finder) confirmInstantiation(
+	namedType *types.Named,
+	u *unifier,
+	candidateParams, targetParams []*types.TypeParam,
+) (typeMatch, bool) {
+	var typeArgs, interfaceTypeArgs []types.Type
+
+	candidate := types.Type(namedType)
+	target := f.iface
+
+	if len(candidateParams) > 0 {
+		typeArgs = u.resolve(candidateParams)
+
+		instance, err := types.Instantiate(nil, namedType, typeArgs, true)
+		if err != nil {
+			return typeMatch{}, false
+		}
+
+		candidate = instance
+	}
+
+	if len(targetParams) > 0 {
+		interfaceTypeArgs = u.resolve(targetParams)
+
+		instance, err := types.Instantiate(nil, f.ifaceGeneric, interfaceTypeArgs, true)
+		if err != nil {
+			return typeMatch{}, false
+		}
+
+		target, _ = instance.Underlying().(*types.Interface)
+	}
+
+	match := methodSetMatch(candidate, target)
+	match.typeArgs = typeArgs
+	match.interfaceTypeArgs = interfaceTypeArgs
+
+	return match, match.implements()
+}
+
+// methodSetMatch checks typ and *typ against iface separately. The method set
+// of *typ includes that of typ, so a value match implies a pointer match,
+// except for interface types: a pointer to an interface has no methods.
+func methodSetMatch(typ types.Type, iface *types.Interface) typeMatch {
+	return typeMatch{
+		valueImplements:   types.Implements(typ, iface),
+		pointerImplements: types.Implements(types.NewPointer(typ), iface),
+	}
+}
+
+func (m typeMatch) implements() bool {
+	return m.valueImplements || m.pointerImplements
+}
+
+func typeParamList(list *types.TypeParamList) []*types.TypeParam {
+	params := make([]*types.TypeParam, 0, list.Len())
+	for param := range list.TypeParams() {
+		params = append(params, param)
+	}
+
+	return params
+}
+
+func typesOf(params []*types.TypeParam) []types.Type {
+	typeList := make([]types.Type, len(params))
+	for i, param := range params {
+		typeList[i] = param
+	}
+
+	return typeList
+}
+
+// typeStrings renders types with fully qualified package paths, the same
+// form the -interface flag accepts for type arguments.
+func typeStrings(typeList []types.Type) []string {
+	if len(typeList) == 0 {
+		return nil
+	}
+
+	strs := make([]string, len(typeList))
+	for i, typ := range typeList {
+		strs[i] = types.TypeString(typ, nil)
+	}
+
+	return strs
+}
+
+// unifier binds type parameters by structurally matching two types. It only
+// has to be good enough to propose an instantiation: the result is always
+// confirmed with types.Instantiate and types.Implements.
+type unifier struct {
+	bindable map[*types.TypeParam]bool
+	bindings map[*types.TypeParam]types.Type
+}
+
+func newUnifier(params []*types.TypeParam) *unifier {
+	u := &unifier{
+		bindable: make(map[*types.TypeParam]bool, len(params)),
+		bindings: make(map[*types.TypeParam]types.Type, len(params)),
+	}
+
+	for _, param := range params {
+		u.bindable[param] = true
+	}
+
+	return u
+}
+
+// resolve returns the binding of each parameter, or the parameter itself when
+// it is unbound.
+func (u *unifier) resolve(params []*types.TypeParam) []types.Type {
+	resolved := make([]types.Type, len(params))
+	for i, param := range params {
+		resolved[i] = u.deref(param)
+	}
+
+	return resolved
+}
+
+func (u *unifier) deref(typ types.Type) types.Type {
+	for {
+		param, ok := typ.(*types.TypeParam)
+		if !ok || !u.bindable[param] {
+			return typ
+		}
+
+		bound, ok := u.bindings[param]
+		if !ok {
+			return typ
+		}
+
+		typ = bound
+	}
+}
+
+// bind binds whichever of x and y is an unbound type parameter to the other
+// and reports whether it did.
+func (u *unifier) bind(x, y types.Type) bool {
+	if param, ok := x.(*types.TypeParam); ok && u.bindable[param] {
+		if x != y {
+			u.bindings[param] = y
+		}
+
+		return true
+	}
+
+	if param, ok := y.(*types.TypeParam); ok && u.bindable[param] {
+		u.bindings[param] = x
+
+		return true
+	}
+
+	return false
+}
+
+func (u *unifier) unify(x, y types.Type) bool {
+	x = u.deref(types.Unalias(x))
+	y = u.deref(types.Unalias(y))
+
+	if u.bind(x, y) {
+		return true
+	}
+
+	switch x := x.(type) {
+	case *types.Pointer:
+		y, ok := y.(*types.Pointer)
+
+		return ok && u.unify(x.Elem(), y.Elem())
+	case *types.Slice:
+		y, ok := y.(*types.Slice)
+
+		return ok && u.unify(x.Elem(), y.Elem())
+	case *types.Array:
+		y, ok := y.(*types.Array)
+
+		return ok && x.Len() == y.Len() && u.unify(x.Elem(), y.Elem())
+	case *types.Map:
+		y, ok := y.(*types.Map)
+
+		return ok && u.unify(x.Key(), y.Key()) && u.unify(x.Elem(), y.Elem())
+	case *types.Chan:
+		y, ok := y.(*types.Chan)
+
+		return ok && x.Dir() == y.Dir() && u.unify(x.Elem(), y.Elem())
+	case *types.Signature:
+		y, ok := y.(*types.Signature)
+
+		return ok && x.Variadic() == y.Variadic() &&
+			u.unifyTuples(x.Params(), y.Params()) &&
+			u.unifyTuples(x.Results(), y.Results())
+	case *types.Named:
+		return u.unifyNamed(x, y)
+	default:
+		return types.Identical(x, y)
+	}
+}
+
+func (u *unifier) unifyNamed(x *types.Named, y types.Type) bool {
+	named, ok := y.(*types.Named)
+	if !ok || x.Origin() != named.Origin() {
+		return false
+	}
+
+	xArgs, yArgs := x.TypeArgs(), named.TypeArgs()
+	if xArgs.Len() != yArgs.Len() {
+		return false
+	}
+
+	for i := range xArgs.Len() {
+		if !u.unify(xArgs.At(i), yArgs.At(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (u *unifier) unifyTuples(x, y *types.Tuple) bool {
+	if x.Len() != y.Len() {
+		return false
+	}
+
+	for i := range x.Len() {
+		if !u.unify(x.At(i).Type(), y.At(i).Type()) {
+			return false
+		}
+	}
+
+	return true
+}
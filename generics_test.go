@@ -0,0 +1,219 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func genericsModule(t *testing.T) string {
+	t.Helper()
+
+	return newTempModule(t, map[string]string{
+		"repo/repo.go": `package repo
+
+type User struct{ ID string }
+
+type Repo[T any] interface {
+	Get(id string) (T, error)
+}
+
+type KV[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V)
+}
+
+type Store interface {
+	Close() error
+}
+`,
+		"impl/impl.go": `package impl
+
+import (
+	"time"
+
+	"example.com/app/repo"
+)
+
+type UserRepo struct{}
+
+func (UserRepo) Get(id string) (repo.User, error) { return repo.User{}, nil }
+
+type TimeRepo struct{}
+
+func (*TimeRepo) Get(id string) (time.Time, error) { return time.Time{}, nil }
+
+type memRepo[T any] struct{}
+
+func (m *memRepo[T]) Get(id string) (T, error) {
+	var zero T
+	return zero, nil
+}
+
+type numRepo[N int | int64] struct{}
+
+func (numRepo[N]) Get(id string) (N, error) { return 0, nil }
+
+type cache[K comparable, V any] struct{}
+
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	var zero V
+	return zero, false
+}
+func (c *cache[K, V]) Set(key K, value V) {}
+
+type stringCache struct{}
+
+func (stringCache) Get(key string) (int, bool) { return 0, false }
+func (stringCache) Set(key string, value int)  {}
+
+type wrongID struct{}
+
+func (wrongID) Get(id int) (string, error) { return "", nil }
+`,
+	})
+}
+
+func TestFinder_GenericInterfaces(t *testing.T) {
+	t.Parallel()
+
+	root := genericsModule(t)
+
+	type found struct {
+		typeArgs          []string
+		interfaceTypeArgs []string
+	}
+
+	testCases := []struct {
+		name     string
+		spec     interfaceSpec
+		expected map[string]found
+	}{
+		{
+			name: "instantiated with a local type",
+			spec: interfaceSpec{ImportPath: "example.com/app/repo", Name: "Repo", TypeArgs: []string{"User"}},
+			expected: map[string]found{
+				"UserRepo": {},
+				"memRepo":  {typeArgs: []string{"example.com/app/repo.User"}},
+			},
+		},
+		{
+			name: "instantiated with an import path qualified type",
+			spec: interfaceSpec{ImportPath: "example.com/app/repo", Name: "Repo", TypeArgs: []string{"time.Time"}},
+			expected: map[string]found{
+				"TimeRepo": {},
+				"memRepo":  {typeArgs: []string{"time.Time"}},
+			},
+		},
+		{
+			name: "instantiated with a basic type",
+			spec: interfaceSpec{ImportPath: "example.com/app/repo", Name: "Repo", TypeArgs: []string{"int"}},
+			expected: map[string]found{
+				"memRepo": {typeArgs: []string{"int"}},
+				"numRepo": {typeArgs: []string{"int"}},
+			},
+		},
+		{
+			name: "any instantiation",
+			spec: interfaceSpec{ImportPath: "example.com/app/repo", Name: "Repo", TypeArgs: []string{"*"}},
+			expected: map[string]found{
+				"UserRepo": {interfaceTypeArgs: []string{"example.com/app/repo.User"}},
+				"TimeRepo": {interfaceTypeArgs: []string{"time.Time"}},
+				"memRepo":  {typeArgs: []string{"T"}, interfaceTypeArgs: []string{"T"}},
+				"numRepo":  {typeArgs: []string{"N"}, interfaceTypeArgs: []string{"N"}},
+			},
+		},
+		{
+			name: "type arguments omitted",
+			spec: interfaceSpec{ImportPath: "example.com/app/repo", Name: "KV"},
+			expected: map[string]found{
+				"stringCache": {interfaceTypeArgs: []string{"string", "int"}},
+				"cache":       {typeArgs: []string{"K", "V"}, interfaceTypeArgs: []string{"K", "V"}},
+			},
+		},
+		{
+			name: "partial wildcard",
+			spec: interfaceSpec{ImportPath: "example.com/app/repo", Name: "KV", TypeArgs: []string{"string", "*"}},
+			expected: map[string]found{
+				"stringCache": {interfaceTypeArgs: []string{"string", "int"}},
+				"cache":       {typeArgs: []string{"string", "V"}, interfaceTypeArgs: []string{"string", "V"}},
+			},
+		},
+		{
+			name: "wildcard before a concrete argument",
+			spec: interfaceSpec{ImportPath: "example.com/app/repo", Name: "KV", TypeArgs: []string{"*", "[]byte"}},
+			expected: map[string]found{
+				"cache": {typeArgs: []string{"K", "[]byte"}, interfaceTypeArgs: []string{"K", "[]byte"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, tc.spec.Name)
+			require.NoError(t, finder.loadInterface(tc.spec))
+			require.NoError(t, finder.scanDirectory(filepath.Join(root, "impl")))
+
+			results := make(map[string]found)
+			for _, result := range finder.getResults() {
+				results[result.Struct] = found{
+					typeArgs:          result.TypeArgs,
+					interfaceTypeArgs: result.InterfaceTypeArgs,
+				}
+			}
+
+			assert.Equal(t, tc.expected, results)
+		})
+	}
+}
+
+func TestFinder_GenericInterfaceErrors(t *testing.T) {
+	t.Parallel()
+
+	root := genericsModule(t)
+
+	testCases := []struct {
+		name        string
+		spec        interfaceSpec
+		expectError error
+	}{
+		{
+			name:        "unknown type argument",
+			spec:        interfaceSpec{ImportPath: "example.com/app/repo", Name: "Repo", TypeArgs: []string{"Nope"}},
+			expectError: ErrInvalidTypeArg,
+		},
+		{
+			name:        "unresolvable qualified type argument",
+			spec:        interfaceSpec{ImportPath: "example.com/app/repo", Name: "Repo", TypeArgs: []string{"example.org/missing.T"}},
+			expectError: ErrInvalidTypeArg,
+		},
+		{
+			name:        "constraint not satisfied",
+			spec:        interfaceSpec{ImportPath: "example.com/app/repo", Name: "KV", TypeArgs: []string{"[]byte", "int"}},
+			expectError: ErrInvalidTypeArg,
+		},
+		{
+			name:        "too many type arguments",
+			spec:        interfaceSpec{ImportPath: "example.com/app/repo", Name: "Repo", TypeArgs: []string{"int", "int"}},
+			expectError: ErrTypeArgCount,
+		},
+		{
+			name:        "type arguments for a non-generic interface",
+			spec:        interfaceSpec{ImportPath: "example.com/app/repo", Name: "Store", TypeArgs: []string{"int"}},
+			expectError: ErrTypeArgCount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, tc.spec.Name)
+			require.ErrorIs(t, finder.loadInterface(tc.spec), tc.expectError)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strings"
+)
+
+// requireFields is the number of fields in a require line: path and version.
+const requireFields = 2
+
+// moduleVersion identifies a module at a version. Path may also be a local
+// directory when it comes from the right-hand side of a replace directive, in
+// which case Version is empty.
+type moduleVersion struct {
+	Path    string
+	Version string
+}
+
+// goModFile holds the parts of a go.mod file needed to resolve imports.
+type goModFile struct {
+	Module   string
+	Requires map[string]string
+	Replaces map[string]moduleVersion
+}
+
+// goWorkFile holds the parts of a go.work file needed to find the modules
+// of a workspace and resolve their imports.
+type goWorkFile struct {
+	Uses     []string
+	Replaces map[string]moduleVersion
+}
+
+// parseGoMod reads the module, require and replace directives from a go.mod
+// file. Everything else is ignored.
+func parseGoMod(content string) (*goModFile, error) {
+	gm := &goModFile{
+		Requires: make(map[string]string),
+		Replaces: make(map[string]moduleVersion),
+	}
+
+	parseDirectives(content, gm.applyDirective)
+
+	if gm.Module == "" {
+		return nil, ErrNoModuleDeclaration
+	}
+
+	return gm, nil
+}
+
+// parseGoWork reads the use and replace directives from a go.work file.
+func parseGoWork(content string) *goWorkFile {
+	gw := &goWorkFile{Replaces: make(map[string]moduleVersion)}
+
+	parseDirectives(content, gw.applyDirective)
+
+	return gw
+}
+
+// parseDirectives calls apply with each directive of a go.mod or go.work
+// file; lines inside a block get the block's verb.
+func parseDirectives(content string, apply func(verb, args string)) {
+	block := ""
+
+	for line := range strings.SplitSeq(content, "\n") {
+		line = stripGoModComment(line)
+		if line == "" {
+			continue
+		}
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+
+				continue
+			}
+
+			apply(block, line)
+
+			continue
+		}
+
+		verb, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		if rest == "(" {
+			block = verb
+
+			continue
+		}
+
+		apply(verb, rest)
+	}
+}
+
+func directiveFields(args string) []string {
+	fields := strings.Fields(args)
+	for i, field := range fields {
+		fields[i] = strings.Trim(field, "\"`")
+	}
+
+	return fields
+}
+
+func (gm *goModFile) applyDirective(verb, args string) {
+	fields := directiveFields(args)
+
+	switch verb {
+	case "module":
+		if len(fields) > 0 {
+			gm.Module = fields[0]
+		}
+	case "require":
+		if len(fields) >= requireFields {
+			gm.Requires[fields[0]] = fields[1]
+		}
+	case "replace":
+		applyReplace(gm.Replaces, fields)
+	}
+}
+
+func (gw *goWorkFile) applyDirective(verb, args string) {
+	fields := directiveFields(args)
+
+	switch verb {
+	case "use":
+		if len(fields) > 0 {
+			gw.Uses = append(gw.Uses, fields[0])
+		}
+	case "replace":
+		applyReplace(gw.Replaces, fields)
+	}
+}
+
+// applyReplace handles both "old => new" and "old v1 => new v2" forms. The
+// version on the left is ignored: the replacement applies to whichever
+// version is required.
+func applyReplace(replaces map[string]moduleVersion, fields []string) {
+	arrow := -1
+
+	for i, field := range fields {
+		if field == "=>" {
+			arrow = i
+
+			break
+		}
+	}
+
+	if arrow < 1 || arrow == len(fields)-1 {
+		return
+	}
+
+	target := moduleVersion{Path: fields[arrow+1]}
+	if arrow+2 < len(fields) {
+		target.Version = fields[arrow+2]
+	}
+
+	replaces[fields[0]] = target
+}
+
+func stripGoModComment(line string) string {
+	if idx := strings.Index(line, "//"); idx >= 0 {
+		line = line[:idx]
+	}
+
+	return strings.TrimSpace(line)
+}
+
+// isLocalReplacement reports whether a replace target is a directory rather
+// than a module path, following the go command's rule.
+func isLocalReplacement(path string) bool {
+	return strings.HasPrefix(path, "./") ||
+		strings.HasPrefix(path, "../") ||
+		strings.HasPrefix(path, "/") ||
+		path == "." || path == ".."
+}
+
+// escapeModulePath applies the module cache's case encoding, where each
+// upper-case letter is replaced by an exclamation mark followed by the
+// letter's lower-case equivalent.
+func escapeModulePath(path string) string {
+	var sb strings.Builder
+
+	for _, r := range path {
+		if 'A' <= r && r <= 'Z' {
+			sb.WriteByte('!')
+			sb.WriteRune(r + ('a' - 'A'))
+
+			continue
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoMod(t *testing.T) {
+	t.Parallel()
+
+	content := `// leading comment
+module "github.com/test/repo" // trailing comment
+
+go 1.24
+
+require github.com/single/dep v1.2.3
+
+require (
+	github.com/block/one v0.1.0
+	github.com/block/two v2.0.0+incompatible // indirect
+)
+
+replace github.com/single/dep => ../dep
+
+replace (
+	github.com/block/one v0.1.0 => github.com/fork/one v0.1.1
+)
+`
+
+	gm, err := parseGoMod(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, "github.com/test/repo", gm.Module)
+	assert.Equal(t, map[string]string{
+		"github.com/single/dep": "v1.2.3",
+		"github.com/block/one":  "v0.1.0",
+		"github.com/block/two":  "v2.0.0+incompatible",
+	}, gm.Requires)
+	assert.Equal(t, map[string]moduleVersion{
+		"github.com/single/dep": {Path: "../dep"},
+		"github.com/block/one":  {Path: "github.com/fork/one", Version: "v0.1.1"},
+	}, gm.Replaces)
+}
+
+func TestParseGoModErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseGoMod("go 1.21\nrequire example.com/test v1.0.0\n")
+	require.ErrorIs(t, err, ErrNoModuleDeclaration)
+}
+
+func TestParseGoWork(t *testing.T) {
+	t.Parallel()
+
+	content := `go 1.24
+
+use ./api // the API module
+
+use (
+	./svc
+	"../shared"
+)
+
+replace github.com/single/dep v1.2.3 => ./dep
+`
+
+	gw := parseGoWork(content)
+
+	assert.Equal(t, []string{"./api", "./svc", "../shared"}, gw.Uses)
+	assert.Equal(t, map[string]moduleVersion{
+		"github.com/single/dep": {Path: "./dep"},
+	}, gw.Replaces)
+}
+
+func TestIsLocalReplacement(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isLocalReplacement("./dep"))
+	assert.True(t, isLocalReplacement("../dep"))
+	assert.True(t, isLocalReplacement("/abs/dep"))
+	assert.False(t, isLocalReplacement("github.com/fork/dep"))
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "lower case", path: "github.com/foo/bar", expected: "github.com/foo/bar"},
+		{name: "upper case", path: "github.com/BurntSushi/toml", expected: "github.com/!burnt!sushi/toml"},
+		{name: "version", path: "v1.0.0-RC1", expected: "v1.0.0-!r!c1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, escapeModulePath(tc.path))
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+	"slices"
+	"strings"
+)
+
+// HierarchyNode is one interface in the -hierarchy tree. The root is the
+// target; every other node's method set includes its parent's, so any
+// implementation of it implements its parent too. Embeds tells whether the
+// interface declares its parent as an embedded interface rather than just
+// repeating its methods.
+type HierarchyNode struct {
+	Package           string           `json:"package"`
+	Interface         string           `json:"interface"`
+	PackagePath       string           `json:"packagePath"`
+	Methods           int              `json:"methods"`
+	Embeds            bool             `json:"embeds"`
+	TypeArgs          []string         `json:"typeArgs,omitempty"`
+	InterfaceTypeArgs []string         `json:"interfaceTypeArgs,omitempty"`
+	InTest            bool             `json:"inTest,omitempty"`
+	TestPackage       string           `json:"testPackage,omitempty"`
+	Children          []*HierarchyNode `json:"children,omitempty"`
+}
+
+// hierarchyMember is a node along with its type, used to work out which
+// node it hangs from.
+type hierarchyMember struct {
+	node  *HierarchyNode
+	named *types.Named
+	iface *types.Interface
+}
+
+// recordHierarchyType remembers the type behind an interface result so the
+// tree can relate results to each other once the scan is done.
+func (f *Finder) recordHierarchyType(impl Implementation, namedType *types.Named) {
+	if f.hierarchyTypes == nil {
+		f.hierarchyTypes = make(map[string]*types.Named)
+	}
+
+	f.hierarchyTypes[impl.PackagePath+"."+impl.Struct] = namedType
+}
+
+// buildHierarchy arranges the interfaces found by a -hierarchy scan into a
+// tree rooted at the target. Each interface hangs from the most specific
+// interface it includes: one it embeds if there is any, otherwise the one
+// with the most methods.
+func (f *Finder) buildHierarchy() *HierarchyNode {
+	root := &HierarchyNode{
+		Interface: f.target.Obj().Name(),
+		Methods:   f.iface.NumMethods(),
+	}
+
+	if pkg := f.target.Obj().Pkg(); pkg != nil {
+		root.Package = pkg.Name()
+		root.PackagePath = pkg.Path()
+	}
+
+	members := make([]hierarchyMember, 0, len(f.results))
+
+	for _, impl := range f.results {
+		named := f.hierarchyTypes[impl.PackagePath+"."+impl.Struct]
+		if named == nil {
+			continue
+		}
+
+		iface, _ := named.Underlying().(*types.Interface)
+		members = append(members, hierarchyMember{
+			node: &HierarchyNode{
+				Package:           impl.Package,
+				Interface:         impl.Struct,
+				PackagePath:       impl.PackagePath,
+				Methods:           iface.NumMethods(),
+				TypeArgs:          impl.TypeArgs,
+				InterfaceTypeArgs: impl.InterfaceTypeArgs,
+				InTest:            impl.InTest,
+				TestPackage:       impl.TestPackage,
+			},
+			named: named,
+			iface: iface,
+		})
+	}
+
+	// Parents always come before their children in this order, which keeps
+	// the tree free of cycles when two interfaces have the same method set.
+	slices.SortFunc(members, func(a, b hierarchyMember) int {
+		return cmp.Or(
+			cmp.Compare(a.node.Methods, b.node.Methods),
+			cmp.Compare(a.node.PackagePath, b.node.PackagePath),
+			cmp.Compare(a.node.Interface, b.node.Interface),
+		)
+	})
+
+	for i, member := range members {
+		parent := root
+		member.node.Embeds = embedsType(member.iface, f.target)
+
+		for _, candidate := range members[:i] {
+			if !includesMethods(member, candidate) {
+				continue
+			}
+
+			embeds := embedsType(member.iface, candidate.named)
+			if member.node.Embeds && !embeds {
+				continue
+			}
+
+			parent = candidate.node
+			member.node.Embeds = embeds
+		}
+
+		parent.Children = append(parent.Children, member.node)
+	}
+
+	sortHierarchy(root)
+
+	return root
+}
+
+// includesMethods reports whether member's method set includes candidate's.
+// Generic interfaces are only related through explicit embedding, since
+// their method sets depend on the type arguments.
+func includesMethods(member, candidate hierarchyMember) bool {
+	if embedsType(member.iface, candidate.named) {
+		return true
+	}
+
+	if member.named.TypeParams().Len() > 0 || candidate.named.TypeParams().Len() > 0 {
+		return false
+	}
+
+	return types.Implements(member.named, candidate.iface)
+}
+
+// embedsType reports whether iface embeds named directly. Types are compared
+// by package path and name so the copies of a package checked together with
+// its tests still match.
+func embedsType(iface *types.Interface, named *types.Named) bool {
+	for embedded := range iface.EmbeddedTypes() {
+		embeddedNamed, ok := types.Unalias(embedded).(*types.Named)
+		if ok && qualifiedName(embeddedNamed) == qualifiedName(named) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func qualifiedName(named *types.Named) string {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+func sortHierarchy(node *HierarchyNode) {
+	slices.SortFunc(node.Children, func(a, b *HierarchyNode) int {
+		return cmp.Or(
+			cmp.Compare(a.PackagePath, b.PackagePath),
+			cmp.Compare(a.Interface, b.Interface),
+		)
+	})
+
+	for _, child := range node.Children {
+		sortHierarchy(child)
+	}
+}
+
+// writeHierarchy renders the tree as indented JSON or as text.
+func writeHierarchy(w io.Writer, root *HierarchyNode, format string) error {
+	if format == formatText {
+		var sb strings.Builder
+
+		sb.WriteString(hierarchyText(root) + "\n")
+		writeHierarchyChildren(&sb, root, "")
+
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("failed to write hierarchy: %w", err)
+		}
+
+		return nil
+	}
+
+	output, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal hierarchy to JSON: %w", err)
+	}
+
+	if _, err := w.Write(append(output, '\n')); err != nil {
+		return fmt.Errorf("failed to write hierarchy: %w", err)
+	}
+
+	return nil
+}
+
+func writeHierarchyChildren(sb *strings.Builder, node *HierarchyNode, prefix string) {
+	for i, child := range node.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(node.Children)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		sb.WriteString(prefix + branch + hierarchyText(child) + "\n")
+		writeHierarchyChildren(sb, child, prefix+indent)
+	}
+}
+
+func hierarchyText(node *HierarchyNode) string {
+	name := node.Interface
+	if node.PackagePath != "" {
+		name = node.PackagePath + "." + name
+	}
+
+	details := []string{fmt.Sprintf("%d methods", node.Methods)}
+	if node.Methods == 1 {
+		details[0] = "1 method"
+	}
+
+	if node.Embeds {
+		details = append(details, "embeds parent")
+	}
+
+	if len(node.TypeArgs) > 0 {
+		details = append(details, "type arguments "+strings.Join(node.TypeArgs, ", "))
+	}
+
+	if node.InTest {
+		details = append(details, "in test")
+	}
+
+	return fmt.Sprintf("%s (%s)", name, strings.Join(details, ", "))
+}
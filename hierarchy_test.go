@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hierarchyModule(t *testing.T) string {
+	t.Helper()
+
+	return newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Reader interface {
+	Read(p []byte) (int, error)
+}
+
+type ReadCloser interface {
+	Reader
+	Close() error
+}
+
+type ReadSeekCloser interface {
+	ReadCloser
+	Seek(offset int64, whence int) (int64, error)
+}
+
+type Source interface {
+	Read(p []byte) (int, error)
+	Close() error
+	Name() string
+}
+
+type Writer interface {
+	Write(p []byte) (int, error)
+}
+
+type File struct{}
+
+func (File) Read(p []byte) (int, error) { return 0, nil }
+`,
+		"other/other.go": `package other
+
+import "example.com/app/app"
+
+type Labeled interface {
+	app.Reader
+	Label() string
+}
+`,
+	})
+}
+
+func TestFinder_BuildHierarchy(t *testing.T) {
+	t.Parallel()
+
+	root := hierarchyModule(t)
+
+	finder := newModuleFinder(t, root, "Reader")
+	finder.hierarchy = true
+	finder.kinds = map[string]bool{kindInterface: true}
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Reader"}))
+	require.NoError(t, finder.scanDirectory(root))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeHierarchy(&buf, finder.buildHierarchy(), formatText))
+
+	expected := `example.com/app/app.Reader (1 method)
+├── example.com/app/app.ReadCloser (2 methods, embeds parent)
+│   ├── example.com/app/app.ReadSeekCloser (3 methods, embeds parent)
+│   └── example.com/app/app.Source (3 methods)
+└── example.com/app/other.Labeled (2 methods, embeds parent)
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestBuildHierarchy_ParentChoice(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		source         string
+		expectedParent string
+		expectedEmbeds bool
+	}{
+		{
+			name: "embedded interface wins over a wider one",
+			source: `package p
+
+type Target interface{ A() }
+
+type Wide interface {
+	A()
+	B()
+	C()
+}
+
+type Child interface {
+	Target
+	B()
+	C()
+	D()
+}
+`,
+			expectedParent: "Target",
+			expectedEmbeds: true,
+		},
+		{
+			name: "most methods wins without embedding",
+			source: `package p
+
+type Target interface{ A() }
+
+type Mid interface {
+	A()
+	B()
+}
+
+type Child interface {
+	A()
+	B()
+	C()
+}
+`,
+			expectedParent: "Mid",
+		},
+		{
+			name: "same method set hangs from the first by name",
+			source: `package p
+
+type Target interface{ A() }
+
+type Alpha interface {
+	A()
+	B()
+}
+
+type Child interface {
+	A()
+	B()
+}
+`,
+			expectedParent: "Alpha",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkg := checkSource(t, "example.com/p", tc.source)
+			finder := NewFinder("Target")
+			finder.hierarchy = true
+			finder.kinds = map[string]bool{kindInterface: true}
+			require.NoError(t, finder.setTarget(lookupNamed(t, pkg, "Target")))
+
+			for _, name := range pkg.Scope().Names() {
+				finder.processTypeInScope(pkg.Scope().Lookup(name), ".", pkg)
+			}
+
+			parents := make(map[string]*HierarchyNode)
+			nodes := make(map[string]*HierarchyNode)
+
+			var walk func(node *HierarchyNode)
+			walk = func(node *HierarchyNode) {
+				for _, child := range node.Children {
+					parents[child.Interface] = node
+					nodes[child.Interface] = child
+					walk(child)
+				}
+			}
+			walk(finder.buildHierarchy())
+
+			require.Contains(t, parents, "Child")
+			assert.Equal(t, tc.expectedParent, parents["Child"].Interface)
+			assert.Equal(t, tc.expectedEmbeds, nodes["Child"].Embeds)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Import implements types.Importer.
+func (f *Finder) Import(path string) (*types.Package, error) {
+	return f.ImportFrom(path, "", 0)
+}
+
+// ImportFrom implements types.ImporterFrom. Packages are type-checked from
+// source and cached by import path, so every import is checked once per run
+// and a type reached through two different imports stays identical.
+func (f *Finder) ImportFrom(
+	path, srcDir string, _ types.ImportMode,
+) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+
+	if pkg, ok := f.packages[path]; ok && pkg != nil {
+		return pkg, nil
+	}
+
+	importPath, dir, err := f.resolveImport(path, srcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.loadPackage(importPath, dir)
+}
+
+// loadPackage type-checks the package in dir under importPath, or returns the
+// cached result of an earlier load.
+func (f *Finder) loadPackage(importPath, dir string) (*types.Package, error) {
+	if pkg, ok := f.packages[importPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("%w: %s", ErrImportCycle, importPath)
+		}
+
+		return pkg, nil
+	}
+
+	// Mark the package as in progress so a cycle fails instead of recursing.
+	f.packages[importPath] = nil
+
+	files, err := f.parseImportFiles(dir)
+	if err != nil {
+		delete(f.packages, importPath)
+
+		return nil, err
+	}
+
+	pkg, err := f.typeCheckPackage(importPath, files)
+	if err != nil {
+		delete(f.packages, importPath)
+
+		return nil, err
+	}
+
+	f.packages[importPath] = pkg
+
+	return pkg, nil
+}
+
+// parseImportFiles picks the files of the package in dir. Packages of the
+// scanned module go through parsePackageFiles like the directory walk does;
+// the standard library and dependencies are full of platform-specific files,
+// so those are selected with the build context's constraint rules.
+func (f *Finder) parseImportFiles(dir string) ([]*ast.File, error) {
+	if f.inModule(dir) {
+		return f.parsePackageFiles(dir)
+	}
+
+	bp, err := f.buildContext.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read package in %s: %w",
+			dir,
+			err,
+		)
+	}
+
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+
+	for _, name := range bp.GoFiles {
+		file, err := parser.ParseFile(
+			f.fset,
+			filepath.Join(dir, name),
+			nil,
+			parser.SkipObjectResolution,
+		)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// resolveImport maps an import path to the canonical path it is cached under
+// and the directory holding its source. It never touches the network:
+// packages come from GOROOT, the scanned module or workspace, the vendor
+// directory or the local module cache.
+func (f *Finder) resolveImport(path, srcDir string) (string, string, error) {
+	if isStdImportPath(path) {
+		dir := filepath.Join(f.goroot, "src", path)
+		if isDir(dir) {
+			return path, dir, nil
+		}
+	}
+
+	// Standard library packages import their own vendored copies of
+	// golang.org/x modules, which live under GOROOT/src/vendor.
+	if srcDir != "" && isWithin(filepath.Join(f.goroot, "src"), srcDir) {
+		dir := filepath.Join(f.goroot, "src", "vendor", path)
+		if isDir(dir) {
+			return "vendor/" + path, dir, nil
+		}
+	}
+
+	if module := f.moduleForImport(path); module != nil {
+		rel := strings.TrimPrefix(strings.TrimPrefix(path, module.Path), "/")
+
+		return path, filepath.Join(module.Root, filepath.FromSlash(rel)), nil
+	}
+
+	if dir := f.vendorDir(path); dir != "" {
+		return path, dir, nil
+	}
+
+	if dir := f.moduleCacheDir(path, srcDir); dir != "" {
+		return path, dir, nil
+	}
+
+	return "", "", fmt.Errorf("%w: %s", ErrImportNotFound, path)
+}
+
+// vendorDir returns the directory of path inside the module's vendor tree.
+// As with the go command, the vendor tree only counts when it has a
+// modules.txt.
+func (f *Finder) vendorDir(path string) string {
+	vendorRoot := f.vendorRoot()
+	if !isFile(filepath.Join(vendorRoot, "modules.txt")) {
+		return ""
+	}
+
+	dir := filepath.Join(vendorRoot, filepath.FromSlash(path))
+	if !isDir(dir) {
+		return ""
+	}
+
+	return dir
+}
+
+// moduleCacheDir finds the required module that provides path, honoring
+// replace directives, and returns the package's directory in the module
+// cache or in the local replacement. In a workspace, the requirements of the
+// module importing from srcDir are tried first.
+func (f *Finder) moduleCacheDir(path, srcDir string) string {
+	for _, module := range f.requirementOrder(srcDir) {
+		if dir := f.moduleCacheDirFor(path, module); dir != "" {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+func (f *Finder) moduleCacheDirFor(path string, module workspaceModule) string {
+	if module.GoMod == nil {
+		return ""
+	}
+
+	modPath := ""
+
+	for requirement := range module.GoMod.Requires {
+		if hasPathPrefix(path, requirement) && len(requirement) > len(modPath) {
+			modPath = requirement
+		}
+	}
+
+	if modPath == "" {
+		return ""
+	}
+
+	rel := filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(path, modPath), "/"))
+	root, _ := f.requirementRoot(modPath, module)
+
+	return existingDir(filepath.Join(root, rel))
+}
+
+// requirementRoot returns where the source of the module modPath, as module
+// requires it, lives: in the module cache or in a local replacement. The
+// version returned is the one that gets read, empty for a local replacement.
+func (f *Finder) requirementRoot(modPath string, module workspaceModule) (string, string) {
+	target := moduleVersion{Path: modPath, Version: module.GoMod.Requires[modPath]}
+
+	// go.work replacements override those of the modules, and relative
+	// ones are relative to the go.mod or go.work that declares them.
+	replacement, ok := f.workReplaces[modPath]
+	replacementBase := filepath.Dir(f.workFile)
+
+	if !ok {
+		replacement, ok = module.GoMod.Replaces[modPath]
+		replacementBase = module.Root
+	}
+
+	if ok {
+		if isLocalReplacement(replacement.Path) {
+			root := replacement.Path
+			if !filepath.IsAbs(root) {
+				root = filepath.Join(replacementBase, root)
+			}
+
+			return root, ""
+		}
+
+		target = replacement
+	}
+
+	root := filepath.Join(
+		f.modCache,
+		escapeModulePath(target.Path)+"@"+escapeModulePath(target.Version),
+	)
+
+	return root, target.Version
+}
+
+// inModule reports whether dir belongs to the source tree of the scanned
+// module or one of the workspace's modules.
+func (f *Finder) inModule(dir string) bool {
+	module := f.moduleForDir(dir)
+	if module == nil {
+		return false
+	}
+
+	root, err := filepath.Abs(module.Root)
+	if err != nil {
+		return false
+	}
+
+	return !isWithin(filepath.Join(root, "vendor"), dir)
+}
+
+// isStdImportPath reports whether path looks like a standard library import:
+// as with the go command, a first path element without a dot.
+func isStdImportPath(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+
+	return !strings.Contains(first, ".")
+}
+
+// hasPathPrefix reports whether path is prefix or lies below it.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// isWithin reports whether dir is root or one of its descendants.
+func isWithin(root, dir string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(root, absDir)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func existingDir(dir string) string {
+	if !isDir(dir) {
+		return ""
+	}
+
+	return dir
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTree creates files (keyed by slash-separated relative path) under root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+// newTempModule creates a temporary example.com/app module holding files and
+// returns its root.
+func newTempModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"go.mod": "module example.com/app\n\ngo 1.24\n"})
+	writeTree(t, root, files)
+
+	return root
+}
+
+// newModuleFinder returns a finder rooted at root with its go.mod loaded
+// without changing the process working directory.
+func newModuleFinder(t *testing.T, root, interfaceName string) *Finder {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	require.NoError(t, err)
+
+	goMod, err := parseGoMod(string(content))
+	require.NoError(t, err)
+
+	finder := NewFinder(interfaceName)
+	finder.goMod = goMod
+	finder.modulePath = goMod.Module
+	finder.moduleRoot = root
+
+	return finder
+}
+
+func TestFinder_ImportStdlib(t *testing.T) {
+	t.Parallel()
+
+	finder := NewFinder("Reader")
+
+	pkg, err := finder.Import("io")
+	require.NoError(t, err)
+	assert.Equal(t, "io", pkg.Path())
+
+	reader := lookupInterface(t, pkg, "Reader")
+	assert.Equal(t, 1, reader.NumMethods())
+
+	// Imports are cached, so the same package comes back.
+	again, err := finder.Import("io")
+	require.NoError(t, err)
+	assert.Same(t, pkg, again)
+
+	unsafePkg, err := finder.Import("unsafe")
+	require.NoError(t, err)
+	assert.Same(t, types.Unsafe, unsafePkg)
+}
+
+func TestFinder_ImportResolution(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	modCache := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"go.mod": `module example.com/app
+
+require (
+	example.com/Cached v1.0.0
+	example.com/replaced v1.0.0
+	example.com/vendored v1.0.0
+)
+
+replace example.com/replaced => ./local/replaced
+`,
+		"internal/store/store.go":              "package store\ntype Item struct{}\n",
+		"local/replaced/sub/r.go":              "package sub\ntype R struct{}\n",
+		"vendor/modules.txt":                   "# example.com/vendored v1.0.0\nexample.com/vendored/pkg\n",
+		"vendor/example.com/vendored/pkg/v.go": "package pkg\ntype V struct{}\n",
+		"cycle/a/a.go":                         "package a\nimport _ \"example.com/app/cycle/b\"\n",
+		"cycle/b/b.go":                         "package b\nimport _ \"example.com/app/cycle/a\"\n",
+	})
+	writeTree(t, modCache, map[string]string{
+		"example.com/!cached@v1.0.0/lib/lib.go": "package lib\ntype Lib struct{}\n",
+	})
+
+	finder := newModuleFinder(t, root, "Any")
+	finder.modCache = modCache
+
+	testCases := []struct {
+		name        string
+		path        string
+		expectedDir string
+		expectError bool
+	}{
+		{name: "module package", path: "example.com/app/internal/store", expectedDir: filepath.Join(root, "internal", "store")},
+		{name: "module cache", path: "example.com/Cached/lib", expectedDir: filepath.Join(modCache, "example.com", "!cached@v1.0.0", "lib")},
+		{name: "local replace", path: "example.com/replaced/sub", expectedDir: filepath.Join(root, "local", "replaced", "sub")},
+		{name: "vendor", path: "example.com/vendored/pkg", expectedDir: filepath.Join(root, "vendor", "example.com", "vendored", "pkg")},
+		{name: "stdlib", path: "net/http", expectedDir: filepath.Join(finder.goroot, "src", "net", "http")},
+		{name: "unknown module", path: "example.org/missing", expectError: true},
+		{name: "missing package in cached module", path: "example.com/Cached/nope", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			importPath, dir, err := finder.resolveImport(tc.path, root)
+
+			if tc.expectError {
+				require.ErrorIs(t, err, ErrImportNotFound)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.path, importPath)
+			assert.Equal(t, tc.expectedDir, dir)
+		})
+	}
+
+	t.Run("import cycle", func(t *testing.T) {
+		t.Parallel()
+
+		cycleFinder := newModuleFinder(t, root, "Any")
+
+		// The cycle is reported to the type checker, which keeps going.
+		pkg, err := cycleFinder.Import("example.com/app/cycle/a")
+		require.NoError(t, err)
+		assert.Equal(t, "a", pkg.Name())
+
+		// A package that is still being loaded cannot be loaded again.
+		cycleFinder.packages["example.com/app/cycle/c"] = nil
+		_, err = cycleFinder.loadPackage("example.com/app/cycle/c", filepath.Join(root, "cycle", "a"))
+		require.ErrorIs(t, err, ErrImportCycle)
+	})
+}
+
+func TestFinder_ImportStdlibVendor(t *testing.T) {
+	t.Parallel()
+
+	finder := NewFinder("Any")
+
+	// net/http imports golang.org/x/net packages from GOROOT/src/vendor.
+	srcDir := filepath.Join(finder.goroot, "src", "net", "http")
+	vendored := filepath.Join(finder.goroot, "src", "vendor", "golang.org", "x", "net", "http", "httpguts")
+
+	if !isDir(vendored) {
+		t.Skip("GOROOT has no vendored golang.org/x/net")
+	}
+
+	importPath, dir, err := finder.resolveImport("golang.org/x/net/http/httpguts", srcDir)
+	require.NoError(t, err)
+	assert.Equal(t, "vendor/golang.org/x/net/http/httpguts", importPath)
+	assert.Equal(t, vendored, dir)
+}
+
+func TestFinder_ImportedSignatures(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"store/store.go": `package store
+
+import (
+	"context"
+
+	"example.com/app/model"
+)
+
+type Store interface {
+	Get(ctx context.Context, id string) (model.Item, error)
+}
+`,
+		"model/model.go": "package model\n\ntype Item struct{ ID string }\n",
+		"impl/impl.go": `package impl
+
+import (
+	"context"
+	"io"
+
+	"example.com/app/model"
+)
+
+type Good struct{}
+
+func (Good) Get(ctx context.Context, id string) (model.Item, error) { return model.Item{}, nil }
+
+type WrongContext struct{}
+
+func (WrongContext) Get(r io.Reader, id string) (model.Item, error) { return model.Item{}, nil }
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Store")
+	require.NoError(t, finder.parseInterface(filepath.Join(root, "store", "store.go")))
+	require.NoError(t, finder.scanDirectory(filepath.Join(root, "impl")))
+
+	results := finder.getResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, "Good", results[0].Struct)
+	assert.Equal(t, "example.com/app/impl", results[0].PackagePath)
+}
+
+func TestIsStdImportPath(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isStdImportPath("io"))
+	assert.True(t, isStdImportPath("net/http"))
+	assert.False(t, isStdImportPath("github.com/foo/bar"))
+	assert.False(t, isStdImportPath("example.com"))
+}
+
+func TestIsWithin(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	assert.True(t, isWithin(root, root))
+	assert.True(t, isWithin(root, filepath.Join(root, "a", "b")))
+	assert.False(t, isWithin(root, filepath.Dir(root)))
+	assert.False(t, isWithin(filepath.Join(root, "a"), filepath.Join(root, "ab")))
+}
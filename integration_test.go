@@ -171,11 +171,11 @@ func TestParseInterfaceSpecIntegration(t *testing.T) {
 	require.NoError(t, err, "failed to create interface file")
 
 	spec := interfaceFile + ":TestInterface"
-	file, name, err := parseInterfaceSpec(spec)
+	parsed, err := parseInterfaceSpec(spec)
 	require.NoError(t, err, "parseInterfaceSpec failed")
 
-	assert.Equal(t, interfaceFile, file)
-	assert.Equal(t, "TestInterface", name)
+	assert.Equal(t, interfaceFile, parsed.File)
+	assert.Equal(t, "TestInterface", parsed.Name)
 }
 
 // not parallel: os.Chdir mutates process-wide working directory
@@ -239,5 +239,5 @@ func (w *WebServer) Stop() error { return nil }
 	}()
 
 	// This will output to stdout, but at least tests the function
-	runFinder(interfaceFile, "Server", searchDir)
+	runFinder(interfaceSpec{File: interfaceFile, Name: "Server"}, runOptions{searchDir: searchDir})
 }
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// readInterfaceList reads the file -interfaces-from names.
+func readInterfaceList(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interface list: %w", err)
+	}
+
+	return parseInterfaceList(string(content)), nil
+}
+
+// parseInterfaceList splits an interface list into its specs, one per line.
+// Blank lines are skipped and "#" starts a comment.
+func parseInterfaceList(content string) []string {
+	var specs []string
+
+	for line := range strings.SplitSeq(content, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+
+		line = strings.TrimSpace(line)
+		if line != "" {
+			specs = append(specs, line)
+		}
+	}
+
+	return specs
+}
+
+// runInterfaces searches for the implementations of several interfaces in
+// one run and prints them as one JSON object keyed by interface.
+func runInterfaces(specs []interfaceSpec, opts runOptions) error {
+	for _, spec := range specs {
+		if err := validateArgs(spec, opts.searchDir); err != nil {
+			return err
+		}
+	}
+
+	if err := validateNearMiss(opts.nearMiss); err != nil {
+		return err
+	}
+
+	if err := validateFormat(opts.format); err != nil {
+		return err
+	}
+
+	if err := validateBuildTarget(opts); err != nil {
+		return err
+	}
+
+	if err := validateInterfaces(opts); err != nil {
+		return err
+	}
+
+	perInterface, err := findInterfaceImplementations(specs, opts)
+	if err != nil {
+		return err
+	}
+
+	return writeInterfaceImplementations(specs, perInterface, opts)
+}
+
+// findInterfaceImplementations scans for each interface in turn, for the
+// selected build target or, with -platforms, once per platform, merging the
+// results of each interface.
+func findInterfaceImplementations(
+	specs []interfaceSpec, opts runOptions,
+) ([][]Implementation, error) {
+	if len(opts.platforms) == 0 {
+		return scanInterfaces(specs, opts, opts.target)
+	}
+
+	perPlatform := make([][][]Implementation, 0, len(opts.platforms))
+
+	for _, target := range opts.platforms {
+		perInterface, err := scanInterfaces(specs, opts, target)
+		if err != nil {
+			return nil, fmt.Errorf("platform %s: %w", target, err)
+		}
+
+		perPlatform = append(perPlatform, perInterface)
+	}
+
+	merged := make([][]Implementation, len(specs))
+
+	for i := range specs {
+		results := make([][]Implementation, 0, len(perPlatform))
+		for _, perInterface := range perPlatform {
+			results = append(results, perInterface[i])
+		}
+
+		merged[i] = mergePlatformResults(opts.platforms, results)
+	}
+
+	return merged, nil
+}
+
+// scanInterfaces scans for each interface with one finder, so every package
+// is parsed and type-checked once however many interfaces there are.
+func scanInterfaces(
+	specs []interfaceSpec, opts runOptions, target platform,
+) ([][]Implementation, error) {
+	finder, err := newScanFinder(opts, target)
+	if err != nil {
+		return nil, err
+	}
+
+	perInterface := make([][]Implementation, 0, len(specs))
+
+	for _, spec := range specs {
+		if err := useInterfaceSpec(finder, spec); err != nil {
+			return nil, fmt.Errorf("interface %s: %w", spec, err)
+		}
+
+		if err := scanSearch(finder, opts); err != nil {
+			return nil, err
+		}
+
+		slog.Debug("scan complete",
+			"interface", spec,
+			"platform", target,
+			"implementations", len(finder.results),
+		)
+
+		perInterface = append(perInterface, finder.getResults())
+	}
+
+	return perInterface, nil
+}
+
+// writeInterfaceImplementations prints the results as a JSON object keyed by
+// interface and, with -require-assertions, fails if any lacks an assertion.
+func writeInterfaceImplementations(
+	specs []interfaceSpec, perInterface [][]Implementation, opts runOptions,
+) error {
+	byInterface := make(map[string][]Implementation, len(specs))
+	for i, spec := range specs {
+		byInterface[spec.String()] = perInterface[i]
+	}
+
+	output, err := json.MarshalIndent(byInterface, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal implementations to JSON: %w", err)
+	}
+
+	if _, err := os.Stdout.Write(append(output, '\n')); err != nil {
+		return fmt.Errorf("failed to write output to stdout: %w", err)
+	}
+
+	if !opts.requireAssertions {
+		return nil
+	}
+
+	var errs []error
+
+	for i, spec := range specs {
+		if err := checkAssertions(perInterface[i]); err != nil {
+			errs = append(errs, fmt.Errorf("interface %s: %w", spec, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
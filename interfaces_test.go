@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterfaceList(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "one per line",
+			content:  "io.Reader\nio.Writer\n",
+			expected: []string{"io.Reader", "io.Writer"},
+		},
+		{
+			name: "comments and blank lines",
+			content: `# stdlib
+io.Reader
+
+  ./store/store.go:Store  # ours
+error
+`,
+			expected: []string{"io.Reader", "./store/store.go:Store", "error"},
+		},
+		{
+			name:    "empty",
+			content: "\n# nothing\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, parseInterfaceList(tc.content))
+		})
+	}
+}
+
+func TestReadInterfaceList(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "interfaces.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# checked in CI\nio.Reader\nio.Writer\n"), 0o644))
+
+	specs, err := readInterfaceList(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"io.Reader", "io.Writer"}, specs)
+
+	_, err = readInterfaceList(filepath.Join(t.TempDir(), "missing.txt"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRunInterfaces(t *testing.T) {
+	// not parallel: subtests change the working directory and os.Stdout
+
+	testCases := []struct {
+		name        string
+		specs       []string
+		opts        runOptions
+		expected    map[string]map[string][]string
+		expectedErr error
+		errContains []string
+	}{
+		{
+			name:  "keyed by interface",
+			specs: []string{"app/app.go:Server", "app/app.go:Stopper", "error"},
+			opts:  runOptions{searchDir: ".", target: platform{GOOS: "linux", GOARCH: "amd64"}},
+			expected: map[string]map[string][]string{
+				"app/app.go:Server":  {"Web": nil, "Linux": nil},
+				"app/app.go:Stopper": {"Web": nil},
+				"error":              {},
+			},
+		},
+		{
+			name:  "platforms merged per interface",
+			specs: []string{"app/app.go:Server", "app/app.go:Stopper"},
+			opts: runOptions{
+				searchDir: ".",
+				platforms: []platform{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "windows", GOARCH: "amd64"}},
+			},
+			expected: map[string]map[string][]string{
+				"app/app.go:Server": {
+					"Web":   {"linux/amd64", "windows/amd64"},
+					"Linux": {"linux/amd64"},
+				},
+				"app/app.go:Stopper": {"Web": {"linux/amd64", "windows/amd64"}},
+			},
+		},
+		{
+			name:  "require assertions of every interface",
+			specs: []string{"app/app.go:Server", "app/app.go:Stopper"},
+			opts: runOptions{
+				searchDir:         ".",
+				target:            platform{GOOS: "linux", GOARCH: "amd64"},
+				requireAssertions: true,
+			},
+			expected: map[string]map[string][]string{
+				"app/app.go:Server":  {"Web": nil, "Linux": nil},
+				"app/app.go:Stopper": {"Web": nil},
+			},
+			expectedErr: ErrMissingAssertions,
+			errContains: []string{
+				"interface app/app.go:Server: " + ErrMissingAssertions.Error() + ": example.com/app/impl.Linux",
+				"interface app/app.go:Stopper: " + ErrMissingAssertions.Error() + ": example.com/app/impl.Web",
+			},
+		},
+		{
+			name:        "single interface options",
+			specs:       []string{"app/app.go:Server", "app/app.go:Stopper"},
+			opts:        runOptions{searchDir: ".", hierarchy: true},
+			expectedErr: ErrInterfacesConflict,
+		},
+		{
+			name:        "missing interface file",
+			specs:       []string{"app/app.go:Server", "app/missing.go:Stopper"},
+			opts:        runOptions{searchDir: "."},
+			expectedErr: ErrInterfaceFileNotExist,
+		},
+		{
+			name:        "unknown interface",
+			specs:       []string{"app/app.go:Server", "app/app.go:Starter"},
+			opts:        runOptions{searchDir: "."},
+			expectedErr: ErrInterfaceNotFound,
+			errContains: []string{"interface app/app.go:Starter"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := newTempModule(t, map[string]string{
+				"app/app.go": `package app
+
+type Server interface{ Start() error }
+
+type Stopper interface{ Stop() error }
+`,
+				"impl/impl.go": `package impl
+
+import "example.com/app/app"
+
+type Web struct{}
+
+func (*Web) Start() error { return nil }
+func (*Web) Stop() error  { return nil }
+
+var _ app.Server = (*Web)(nil)
+`,
+				"impl/linux_linux.go": `package impl
+
+type Linux struct{}
+
+func (Linux) Start() error { return nil }
+`,
+			})
+
+			t.Chdir(root)
+
+			specs, _, err := parseSearchSpecs(tc.specs, "")
+			require.NoError(t, err)
+
+			output, err := captureStdout(t, func() error {
+				return runInterfaces(specs, tc.opts)
+			})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				for _, text := range tc.errContains {
+					assert.Contains(t, err.Error(), text)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.expected == nil {
+				assert.Empty(t, output)
+
+				return
+			}
+
+			var byInterface map[string][]Implementation
+
+			require.NoError(t, json.Unmarshal([]byte(output), &byInterface))
+
+			got := make(map[string]map[string][]string, len(byInterface))
+			for key, implementations := range byInterface {
+				got[key] = make(map[string][]string, len(implementations))
+				for _, impl := range implementations {
+					got[key][impl.Struct] = impl.Platforms
+				}
+			}
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestFinder_SetInterface(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod": "module example.com/multi\n\ngo 1.24\n",
+		"shapes/shapes.go": `package shapes
+
+type Unit float64
+
+type Shape interface{ Area() Unit }
+
+type Scaler interface{ Scale(by Unit) }
+
+type Square struct{}
+
+func (Square) Area() Unit { return 1 }
+`,
+		"other/circle.go": `package other
+
+import "example.com/multi/shapes"
+
+type Circle struct{}
+
+func (Circle) Area() shapes.Unit { return 3 }
+
+func (*Circle) Scale(by shapes.Unit) {}
+
+type File struct{}
+
+func (*File) Close() error { return nil }
+`,
+		"other/other_test.go": `package other_test
+
+import "example.com/multi/shapes"
+
+type fakeScaler struct{}
+
+func (fakeScaler) Scale(by shapes.Unit) {}
+`,
+	})
+
+	shapesFile := filepath.Join(root, "shapes", "shapes.go")
+	specs := []interfaceSpec{
+		{File: shapesFile, Name: "Shape"},
+		{File: shapesFile, Name: "Scaler"},
+		{ImportPath: "io", Name: "Closer"},
+	}
+
+	expected := [][]string{
+		{"Circle", "Square"},
+		{"Circle", "fakeScaler"},
+		{"File"},
+	}
+
+	finder := newModuleFinder(t, root, "")
+	finder.tests = true
+
+	otherDir := filepath.Join(root, "other")
+
+	var (
+		shapesPkg *types.Package
+		tests     testPackages
+	)
+
+	for i, spec := range specs {
+		require.NoError(t, finder.setInterface(spec))
+		require.NoError(t, finder.scanDirectory(root))
+
+		var names []string
+		for _, impl := range finder.getResults() {
+			names = append(names, impl.Struct)
+		}
+
+		assert.ElementsMatch(t, expected[i], names, "interface %s", spec)
+
+		if i == 0 {
+			shapesPkg = finder.packages["example.com/multi/shapes"]
+			tests = finder.testPackages[otherDir]
+		}
+	}
+
+	// The packages checked by the first scan are reused by the later ones,
+	// the interface's own package and the test packages included.
+	require.NotNil(t, shapesPkg)
+	require.NotNil(t, tests.external)
+	assert.Same(t, shapesPkg, finder.packages["example.com/multi/shapes"])
+	assert.Same(t, tests.external, finder.testPackages[otherDir].external)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"go/token"
 	"log/slog"
 	"os"
 	"strings"
@@ -11,13 +12,52 @@ import (
 	"github.com/psyb0t/slogging/slogconf"
 )
 
-const expectedParts = 2
+const (
+	expectedParts = 2
+
+	// builtinErrorSpec names the predeclared error interface.
+	builtinErrorSpec = "error"
+)
+
+// interfaceSpec identifies the target interface either by the file that
+// declares it or by the import path of its package. Both are empty for the
+// predeclared error interface. TypeArgs instantiate a generic interface; a
+// "*" entry matches any type argument.
+type interfaceSpec struct {
+	File       string
+	ImportPath string
+	Name       string
+	TypeArgs   []string
+}
+
+func (s interfaceSpec) String() string {
+	name := s.Name
+	if len(s.TypeArgs) > 0 {
+		name += "[" + strings.Join(s.TypeArgs, ", ") + "]"
+	}
+
+	switch {
+	case s.File != "":
+		return s.File + ":" + name
+	case s.ImportPath != "":
+		return s.ImportPath + "." + name
+	default:
+		return name
+	}
+}
+
+// typeSpec names a single type by the import path, or directory, of its
+// package.
+type typeSpec struct {
+	ImportPath string
+	Name       string
+}
 
 func setupUsage() {
 	flag.Usage = func() {
 		fmt.Fprintf(
 			os.Stderr,
-			"Usage: %s [options]\n\n",
+			"Usage: %s [options] [packages]\n\n",
 			os.Args[0],
 		)
 
@@ -26,6 +66,12 @@ func setupUsage() {
 			"Find Go interface implementations in a codebase.\n\n",
 		)
 
+		fmt.Fprintf(
+			os.Stderr,
+			"Packages are Go package patterns such as ./... or "+
+				"example.com/mod/pkg/...; without any, -dir is searched.\n\n",
+		)
+
 		fmt.Fprintf(
 			os.Stderr,
 			"Options:\n",
@@ -43,9 +89,77 @@ func setupUsage() {
 			"  %s -interface ./internal/app/server.go:Server -dir ./internal/pkg/\n",
 			os.Args[0],
 		)
+
+		fmt.Fprintf(
+			os.Stderr,
+			"  %s -interface io.Writer -dir ./internal/pkg/\n",
+			os.Args[0],
+		)
+
+		fmt.Fprintf(
+			os.Stderr,
+			"  %s -interface io.Closer ./cmd/... ./internal/...\n",
+			os.Args[0],
+		)
+
+		fmt.Fprintf(
+			os.Stderr,
+			"  %s -interface io.Reader -interface io.Writer ./...\n",
+			os.Args[0],
+		)
+
+		fmt.Fprintf(
+			os.Stderr,
+			"  %s -interface io.Writer -explain ./internal/pkg/log.Sink -format text\n",
+			os.Args[0],
+		)
+
+		fmt.Fprintf(
+			os.Stderr,
+			"  %s -interface io.Closer -dir ./internal/ -emit-assertions diff\n",
+			os.Args[0],
+		)
+
+		fmt.Fprintf(
+			os.Stderr,
+			"  %s -interface io.Reader -hierarchy -format text\n",
+			os.Args[0],
+		)
+
+		fmt.Fprintf(
+			os.Stderr,
+			"  %s -type ./internal/pkg/log.Sink -imported\n",
+			os.Args[0],
+		)
 	}
 }
 
+// stringsFlag collects the values of a flag that can be repeated.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+
+	return nil
+}
+
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
 func logLevel(debug bool) slog.Level {
 	if debug {
 		return slog.LevelDebug
@@ -64,42 +178,109 @@ func configureLogging(debug bool) {
 	)
 }
 
-func runFinder(interfaceFile, interfaceName, searchDir string) error {
-	if err := validateArgs(
-		interfaceFile,
-		interfaceName,
-		searchDir,
-	); err != nil {
-		return err
+// runOptions carries the command line settings besides the interface spec.
+type runOptions struct {
+	searchDir string
+	patterns  []string
+	kinds     map[string]bool
+	valueOnly bool
+	nearMiss  int
+	explain   typeSpec
+	format    string
+	target    platform
+	tags      []string
+	platforms []platform
+	tests     bool
+	subject   typeSpec
+	imported  bool
+	hierarchy bool
+	nested    bool
+	filter    *pathFilter
+	deps      bool
+	std       bool
+
+	assertions        bool
+	requireAssertions bool
+	emitAssertions    string
+}
+
+// scanSearch scans the package patterns given on the command line, or the
+// -dir tree when there are none.
+func scanSearch(finder *Finder, opts runOptions) error {
+	if len(opts.patterns) > 0 {
+		return finder.scanPatterns(opts.patterns)
 	}
 
-	finder := NewFinder(interfaceName)
+	return finder.scanDirectory(opts.searchDir)
+}
 
-	if err := finder.validateGoModRoot(); err != nil {
+func runFinder(spec interfaceSpec, opts runOptions) error {
+	if err := validateArgs(spec, opts.searchDir); err != nil {
 		return err
 	}
 
-	if err := finder.loadModulePath(); err != nil {
+	if err := validateNearMiss(opts.nearMiss); err != nil {
 		return err
 	}
 
-	if err := finder.parseInterface(interfaceFile); err != nil {
+	if err := validateFormat(opts.format); err != nil {
 		return err
 	}
 
-	slog.Debug("found interface methods",
-		"count", len(finder.interfaceMethods),
-		"methods", finder.interfaceMethods,
-	)
+	if err := validateBuildTarget(opts); err != nil {
+		return err
+	}
 
-	if err := finder.scanDirectory(searchDir); err != nil {
+	if err := validateHierarchy(opts); err != nil {
 		return err
 	}
 
-	slog.Debug("scan complete", "implementations", len(finder.results))
+	if err := validateEmitAssertions(opts); err != nil {
+		return err
+	}
+
+	if opts.explain.Name != "" {
+		finder, err := prepareFinder(spec, opts, opts.target)
+		if err != nil {
+			return err
+		}
+
+		explanation, err := finder.explainType(opts.explain.ImportPath, opts.explain.Name)
+		if err != nil {
+			return err
+		}
+
+		return writeExplanation(os.Stdout, explanation, opts.format)
+	}
+
+	if opts.hierarchy {
+		finder, err := prepareFinder(spec, opts, opts.target)
+		if err != nil {
+			return err
+		}
+
+		if err := scanSearch(finder, opts); err != nil {
+			return err
+		}
+
+		return writeHierarchy(os.Stdout, finder.buildHierarchy(), opts.format)
+	}
+
+	if opts.emitAssertions != "" {
+		return emitAssertions(spec, opts)
+	}
+
+	implementations, err := findImplementations(spec, opts)
+	if err != nil {
+		return err
+	}
 
-	implementations := finder.getResults()
+	return writeImplementations(implementations, opts)
+}
 
+// writeImplementations prints the results as JSON and, with
+// -require-assertions, fails if any lacks an assertion.
+func writeImplementations(implementations []Implementation, opts runOptions) error {
 	output, err := json.MarshalIndent(
 		implementations,
 		"",
@@ -117,47 +298,410 @@ func runFinder(interfaceFile, interfaceName, searchDir string) error {
 		return fmt.Errorf("failed to write newline to stdout: %w", err)
 	}
 
+	if opts.requireAssertions {
+		return checkAssertions(implementations)
+	}
+
+	return nil
+}
+
+// emitAssertions generates the assertion files for the implementations
+// found, then writes them and prints the results, or prints the diff.
+func emitAssertions(spec interfaceSpec, opts runOptions) error {
+	finder, err := prepareFinder(spec, opts, opts.target)
+	if err != nil {
+		return err
+	}
+
+	if err := scanSearch(finder, opts); err != nil {
+		return err
+	}
+
+	changes, err := finder.assertionFileChanges()
+	if err != nil {
+		return err
+	}
+
+	if opts.emitAssertions == emitDiff {
+		return finder.writeAssertionDiff(os.Stdout, changes)
+	}
+
+	if err := applyAssertionFileChanges(changes); err != nil {
+		return err
+	}
+
+	slog.Debug("wrote assertion files", "count", len(changes))
+
+	return writeImplementations(finder.getResults(), opts)
+}
+
+// runReverse lists the interfaces the -type subject implements.
+func runReverse(opts runOptions) error {
+	if err := validateArgs(interfaceSpec{}, opts.searchDir); err != nil {
+		return err
+	}
+
+	if err := validateReverse(opts); err != nil {
+		return err
+	}
+
+	finder := NewFinder("")
+	finder.tests = opts.tests
+	finder.nestedModules = opts.nested
+	finder.filter = opts.filter
+	finder.deps = opts.deps
+	finder.std = opts.std
+	finder.setBuildTarget(opts.target.GOOS, opts.target.GOARCH, opts.tags)
+
+	if err := finder.validateGoModRoot(); err != nil {
+		return err
+	}
+
+	if err := finder.loadModulePath(); err != nil {
+		return err
+	}
+
+	if err := finder.loadSubject(opts.subject); err != nil {
+		return err
+	}
+
+	if err := scanSearch(finder, opts); err != nil {
+		return err
+	}
+
+	if opts.imported {
+		finder.scanImportedInterfaces()
+	}
+
+	slog.Debug("reverse scan complete", "interfaces", len(finder.interfaceMatches))
+
+	output, err := json.MarshalIndent(finder.getInterfaceMatches(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal interfaces to JSON: %w", err)
+	}
+
+	if _, err := os.Stdout.Write(append(output, '\n')); err != nil {
+		return fmt.Errorf("failed to write output to stdout: %w", err)
+	}
+
+	return nil
+}
+
+// prepareFinder creates a finder for one build target and loads the module
+// and the target interface. Loaded packages depend on the build target, so
+// every target gets a finder of its own.
+func prepareFinder(
+	spec interfaceSpec, opts runOptions, target platform,
+) (*Finder, error) {
+	finder, err := newScanFinder(opts, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := useInterfaceSpec(finder, spec); err != nil {
+		return nil, err
+	}
+
+	return finder, nil
+}
+
+// newScanFinder creates a finder for one build target and loads the module,
+// leaving the target interface to useInterfaceSpec.
+func newScanFinder(opts runOptions, target platform) (*Finder, error) {
+	finder := NewFinder("")
+	finder.kinds = opts.kinds
+	finder.valueOnly = opts.valueOnly
+	finder.nearMiss = opts.nearMiss
+	finder.tests = opts.tests
+	finder.nestedModules = opts.nested
+	finder.filter = opts.filter
+	finder.deps = opts.deps
+	finder.std = opts.std
+	finder.setBuildTarget(target.GOOS, target.GOARCH, opts.tags)
+
+	if opts.assertions || opts.requireAssertions || opts.emitAssertions != "" {
+		finder.enableAssertions()
+	}
+
+	if opts.hierarchy {
+		finder.hierarchy = true
+		finder.kinds = map[string]bool{kindInterface: true}
+	}
+
+	if err := finder.validateGoModRoot(); err != nil {
+		return nil, err
+	}
+
+	if err := finder.loadModulePath(); err != nil {
+		return nil, err
+	}
+
+	return finder, nil
+}
+
+// useInterfaceSpec makes the interface of spec the finder's target.
+func useInterfaceSpec(finder *Finder, spec interfaceSpec) error {
+	if err := finder.setInterface(spec); err != nil {
+		return err
+	}
+
+	slog.Debug("found interface methods",
+		"interface", spec,
+		"count", finder.iface.NumMethods(),
+		"methods", finder.getInterfaceMethods(finder.iface),
+	)
+
 	return nil
 }
 
-func parseInterfaceSpec(
-	spec string,
-) (string, string, error) {
+// findImplementations scans for the selected build target or, with
+// -platforms, once per platform, merging the results.
+func findImplementations(
+	spec interfaceSpec, opts runOptions,
+) ([]Implementation, error) {
+	if len(opts.platforms) == 0 {
+		return scanTarget(spec, opts, opts.target)
+	}
+
+	perPlatform := make([][]Implementation, 0, len(opts.platforms))
+
+	for _, target := range opts.platforms {
+		implementations, err := scanTarget(spec, opts, target)
+		if err != nil {
+			return nil, fmt.Errorf("platform %s: %w", target, err)
+		}
+
+		perPlatform = append(perPlatform, implementations)
+	}
+
+	return mergePlatformResults(opts.platforms, perPlatform), nil
+}
+
+func scanTarget(
+	spec interfaceSpec, opts runOptions, target platform,
+) ([]Implementation, error) {
+	finder, err := prepareFinder(spec, opts, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scanSearch(finder, opts); err != nil {
+		return nil, err
+	}
+
+	slog.Debug("scan complete",
+		"platform", target,
+		"implementations", len(finder.results),
+	)
+
+	return finder.getResults(), nil
+}
+
+// parseInterfaceSpec accepts "file.go:Name", "importpath:Name",
+// "importpath.Name" and the bare predeclared "error", each optionally followed
+// by type arguments such as "[User]" or "[*]".
+func parseInterfaceSpec(spec string) (interfaceSpec, error) {
+	spec = strings.TrimSpace(spec)
 	if spec == "" {
-		return "", "", ErrInterfaceSpecEmpty
+		return interfaceSpec{}, ErrInterfaceSpecEmpty
+	}
+
+	spec, typeArgs, err := splitTypeArgs(spec)
+	if err != nil {
+		return interfaceSpec{}, err
+	}
+
+	parsed, err := parseInterfaceLocation(spec)
+	if err != nil {
+		return interfaceSpec{}, err
+	}
+
+	parsed.TypeArgs = typeArgs
+
+	return parsed, nil
+}
+
+func parseInterfaceLocation(spec string) (interfaceSpec, error) {
+	if spec == builtinErrorSpec {
+		return interfaceSpec{Name: builtinErrorSpec}, nil
+	}
+
+	if !strings.Contains(spec, ":") {
+		return parseImportPathSpec(spec)
 	}
 
 	parts := strings.Split(spec, ":")
 	if len(parts) != expectedParts {
-		return "", "", ErrInterfaceSpecFormat
+		return interfaceSpec{}, ErrInterfaceSpecFormat
 	}
 
-	interfaceFile := strings.TrimSpace(parts[0])
+	location := strings.TrimSpace(parts[0])
 	interfaceName := strings.TrimSpace(parts[1])
 
-	if interfaceFile == "" {
-		return "", "", ErrInterfaceFilePathEmpty
+	if location == "" {
+		return interfaceSpec{}, ErrInterfaceFilePathEmpty
 	}
 
 	if interfaceName == "" {
-		return "", "", ErrInterfaceNameEmpty
+		return interfaceSpec{}, ErrInterfaceNameEmpty
 	}
 
-	return interfaceFile, interfaceName, nil
+	if strings.HasSuffix(location, ".go") {
+		return interfaceSpec{File: location, Name: interfaceName}, nil
+	}
+
+	return interfaceSpec{ImportPath: location, Name: interfaceName}, nil
+}
+
+// splitTypeArgs cuts a trailing "[...]" type argument list off spec. Type
+// arguments may themselves contain brackets, dots and slashes, so the list is
+// found by matching brackets from the end and split on top-level commas only.
+func splitTypeArgs(spec string) (string, []string, error) {
+	if !strings.HasSuffix(spec, "]") {
+		return spec, nil, nil
+	}
+
+	depth := 0
+	open := -1
+
+	for i := len(spec) - 1; i >= 0 && open < 0; i-- {
+		switch spec[i] {
+		case ']':
+			depth++
+		case '[':
+			depth--
+			if depth == 0 {
+				open = i
+			}
+		}
+	}
+
+	if open <= 0 {
+		return "", nil, ErrInterfaceSpecFormat
+	}
+
+	typeArgs := splitTopLevel(spec[open+1 : len(spec)-1])
+	for i, typeArg := range typeArgs {
+		typeArgs[i] = strings.TrimSpace(typeArg)
+		if typeArgs[i] == "" {
+			return "", nil, ErrInterfaceSpecFormat
+		}
+	}
+
+	return strings.TrimSpace(spec[:open]), typeArgs, nil
+}
+
+// splitTopLevel splits s on commas that are not nested in brackets,
+// parentheses or braces.
+func splitTopLevel(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i, r := range s {
+		switch r {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// parseImportPathSpec splits "importpath.Name" at the last dot, which can
+// never belong to the name.
+func parseImportPathSpec(spec string) (interfaceSpec, error) {
+	if strings.HasSuffix(spec, ".go") {
+		return interfaceSpec{}, ErrInterfaceSpecFormat
+	}
+
+	dot := strings.LastIndex(spec, ".")
+	if dot <= strings.LastIndex(spec, "/") {
+		return interfaceSpec{}, ErrInterfaceSpecFormat
+	}
+
+	importPath := spec[:dot]
+	interfaceName := spec[dot+1:]
+
+	if !token.IsIdentifier(interfaceName) {
+		return interfaceSpec{}, ErrInterfaceSpecFormat
+	}
+
+	return interfaceSpec{ImportPath: importPath, Name: interfaceName}, nil
+}
+
+// parseSearchSpecs parses whichever of -interface and -type was given; a
+// forward search needs at least one interface, a reverse lookup the type.
+// Interfaces given more than once are searched for once.
+func parseSearchSpecs(interfaceArgs []string, typeArg string) ([]interfaceSpec, typeSpec, error) {
+	if typeArg != "" {
+		if len(interfaceArgs) > 0 {
+			return nil, typeSpec{}, ErrTypeWithInterface
+		}
+
+		subject, err := parseTypeSpec(typeArg)
+
+		return nil, subject, err
+	}
+
+	if len(interfaceArgs) == 0 {
+		return nil, typeSpec{}, ErrInterfaceSpecEmpty
+	}
+
+	specs := make([]interfaceSpec, 0, len(interfaceArgs))
+	seen := make(map[string]bool, len(interfaceArgs))
+
+	for _, arg := range interfaceArgs {
+		spec, err := parseInterfaceSpec(arg)
+		if err != nil {
+			return nil, typeSpec{}, err
+		}
+
+		if seen[spec.String()] {
+			continue
+		}
+
+		seen[spec.String()] = true
+		specs = append(specs, spec)
+	}
+
+	return specs, typeSpec{}, nil
+}
+
+// parseTypeSpec accepts "importpath.TypeName" and "importpath:TypeName" for
+// -explain; the import path may also be a "./"-style directory.
+func parseTypeSpec(spec string) (typeSpec, error) {
+	parsed, err := parseInterfaceLocation(strings.TrimSpace(spec))
+	if err != nil || parsed.ImportPath == "" {
+		return typeSpec{}, fmt.Errorf("%w: %q", ErrTypeSpecFormat, spec)
+	}
+
+	return typeSpec{ImportPath: parsed.ImportPath, Name: parsed.Name}, nil
 }
 
 func main() {
 	var (
-		interfaceSpec = flag.String(
-			"interface",
+		interfacesFrom = flag.String(
+			"interfaces-from",
 			"",
-			"Interface specification in format 'file.go:InterfaceName'",
+			"File listing interface specifications, one per line; '#' starts a "+
+				"comment. Output becomes an object keyed by interface",
 		)
 
 		searchDir = flag.String(
 			"dir",
 			".",
-			"Directory to search for implementations",
+			"Directory to search for implementations, when no package patterns "+
+				"are given",
 		)
 
 		help = flag.Bool(
@@ -166,6 +710,141 @@ func main() {
 			"Show help",
 		)
 
+		kinds = flag.String(
+			"kinds",
+			"",
+			"Comma-separated kinds of types to report "+
+				"(struct, func, slice, array, map, basic, pointer, chan, interface); "+
+				"empty reports all",
+		)
+
+		valueOnly = flag.Bool(
+			"value-only",
+			false,
+			"Only report types whose values implement the interface, "+
+				"not just pointers to them",
+		)
+
+		nearMiss = flag.Int(
+			"near-miss",
+			0,
+			"Also report types that miss or mismatch at most N of the "+
+				"interface's methods, with what is wrong; 0 disables",
+		)
+
+		explain = flag.String(
+			"explain",
+			"",
+			"Explain why one type, given as 'importpath.TypeName', does or "+
+				"does not implement the interface, method by method",
+		)
+
+		format = flag.String(
+			"format",
+			formatJSON,
+			"Output format of -explain and -hierarchy: json or text",
+		)
+
+		goos = flag.String(
+			"goos",
+			"",
+			"Target operating system for build constraints (default: host GOOS)",
+		)
+
+		goarch = flag.String(
+			"goarch",
+			"",
+			"Target architecture for build constraints (default: host GOARCH)",
+		)
+
+		tags = flag.String(
+			"tags",
+			"",
+			"Comma-separated build tags, as with go build -tags",
+		)
+
+		tests = flag.Bool(
+			"tests",
+			false,
+			"Also search _test.go files, including external _test packages",
+		)
+
+		nestedModules = flag.Bool(
+			"nested-modules",
+			false,
+			"Descend into directories with their own go.mod instead of stopping "+
+				"at them like the go command",
+		)
+
+		deps = flag.Bool(
+			"deps",
+			false,
+			"Also scan the packages of the module's dependencies, from vendor/ "+
+				"or the module cache, reporting their module and version",
+		)
+
+		std = flag.Bool(
+			"std",
+			false,
+			"Also scan the standard library in GOROOT/src, for the target platform",
+		)
+
+		gitignore = flag.Bool(
+			"gitignore",
+			false,
+			"Skip what the .gitignore files from the module root down ignore",
+		)
+
+		typeName = flag.String(
+			"type",
+			"",
+			"Reverse lookup: list the interfaces that this type, given as "+
+				"'importpath.TypeName', implements (instead of -interface)",
+		)
+
+		hierarchy = flag.Bool(
+			"hierarchy",
+			false,
+			"Report the interfaces that embed the target or include its methods, "+
+				"as a tree",
+		)
+
+		assertions = flag.Bool(
+			"assertions",
+			false,
+			"Report the compile-time assertions (var _ I = (*T)(nil)) of each "+
+				"implementation, and stale ones",
+		)
+
+		requireAssertions = flag.Bool(
+			"require-assertions",
+			false,
+			"Like -assertions, but exit non-zero when an implementation has no "+
+				"assertion or one is stale",
+		)
+
+		emitAssertions = flag.String(
+			"emit-assertions",
+			"",
+			"Generate a "+assertionFileName+" per package asserting the "+
+				"implementations found: 'write' writes the files, 'diff' prints "+
+				"the changes instead",
+		)
+
+		imported = flag.Bool(
+			"imported",
+			false,
+			"With -type, also search the standard library and dependency "+
+				"packages the module imports",
+		)
+
+		platforms = flag.String(
+			"platforms",
+			"",
+			"Comma-separated GOOS/GOARCH list, e.g. 'linux/amd64,windows/amd64'; "+
+				"scans each and reports which platforms every implementation exists on",
+		)
+
 		debug = flag.Bool(
 			"debug",
 			false,
@@ -173,6 +852,31 @@ func main() {
 		)
 	)
 
+	var interfaceArgs, excludes, includes stringsFlag
+
+	flag.Var(
+		&interfaceArgs,
+		"interface",
+		"Interface specification: 'file.go:InterfaceName', "+
+			"'importpath.InterfaceName', 'importpath:InterfaceName' or 'error'. "+
+			"Generic interfaces take type arguments, e.g. 'Repo[User]' or 'Repo[*]'. "+
+			"Repeatable; with several, output is an object keyed by interface",
+	)
+
+	flag.Var(
+		&excludes,
+		"exclude",
+		"Skip directories and files matching this glob, or regexp when "+
+			"prefixed with 're:', relative to the module root; repeatable",
+	)
+
+	flag.Var(
+		&includes,
+		"include",
+		"Only report types from files matching this glob or 're:' regexp, "+
+			"or inside directories matching it; repeatable",
+	)
+
 	setupUsage()
 	flag.Parse()
 
@@ -183,21 +887,130 @@ func main() {
 		os.Exit(0)
 	}
 
-	interfaceFile, interfaceName, err := parseInterfaceSpec(
-		*interfaceSpec,
-	)
+	// The output shape follows what was asked for, so repeating one -interface
+	// still prints the object keyed by interface.
+	multiple := len(interfaceArgs) > 1 || *interfacesFrom != ""
+
+	if *interfacesFrom != "" {
+		listed, err := readInterfaceList(*interfacesFrom)
+		if err != nil {
+			slog.Error("failed to read interface list", "err", err)
+			os.Exit(1)
+		}
+
+		interfaceArgs = append(interfaceArgs, listed...)
+	}
+
+	specs, subject, err := parseSearchSpecs(interfaceArgs, *typeName)
+	if err != nil {
+		slog.Error("failed to parse arguments", "err", err)
+		os.Exit(1)
+	}
+
+	patterns := flag.Args()
+	if err := validatePatterns(patterns, isFlagSet("dir")); err != nil {
+		slog.Error("failed to parse package patterns", "err", err)
+		os.Exit(1)
+	}
+
+	filter, err := newPathFilter(excludes, includes, *gitignore)
 	if err != nil {
-		slog.Error("failed to parse interface spec", "err", err)
+		slog.Error("failed to parse path filters", "err", err)
+		os.Exit(1)
+	}
+
+	kindSet, err := parseKinds(*kinds)
+	if err != nil {
+		slog.Error("failed to parse kinds", "err", err)
+		os.Exit(1)
+	}
+
+	var explainSpec typeSpec
+	if *explain != "" {
+		explainSpec, err = parseTypeSpec(*explain)
+		if err != nil {
+			slog.Error("failed to parse explain type", "err", err)
+			os.Exit(1)
+		}
+	}
+
+	platformList, err := parsePlatforms(*platforms)
+	if err != nil {
+		slog.Error("failed to parse platforms", "err", err)
 		os.Exit(1)
 	}
 
 	slog.Debug("parsed arguments",
-		"interface_file", interfaceFile,
-		"interface_name", interfaceName,
+		"interfaces", specs,
+		"interfaces_from", *interfacesFrom,
 		"search_dir", *searchDir,
+		"patterns", patterns,
+		"kinds", *kinds,
+		"value_only", *valueOnly,
+		"near_miss", *nearMiss,
+		"explain", *explain,
+		"format", *format,
+		"goos", *goos,
+		"goarch", *goarch,
+		"tags", *tags,
+		"platforms", *platforms,
+		"tests", *tests,
+		"nested_modules", *nestedModules,
+		"exclude", excludes,
+		"include", includes,
+		"gitignore", *gitignore,
+		"deps", *deps,
+		"std", *std,
+		"type", *typeName,
+		"imported", *imported,
+		"hierarchy", *hierarchy,
+		"assertions", *assertions,
+		"require_assertions", *requireAssertions,
+		"emit_assertions", *emitAssertions,
 	)
 
-	if err := runFinder(interfaceFile, interfaceName, *searchDir); err != nil {
+	// -format only reaches the run when given, so -type can tell it apart from
+	// the JSON default.
+	formatArg := ""
+	if isFlagSet("format") {
+		formatArg = *format
+	}
+
+	opts := runOptions{
+		searchDir: *searchDir,
+		patterns:  patterns,
+		kinds:     kindSet,
+		valueOnly: *valueOnly,
+		nearMiss:  *nearMiss,
+		explain:   explainSpec,
+		format:    formatArg,
+		target:    platform{GOOS: *goos, GOARCH: *goarch},
+		tags:      parseTags(*tags),
+		platforms: platformList,
+		tests:     *tests,
+		subject:   subject,
+		imported:  *imported,
+		hierarchy: *hierarchy,
+		nested:    *nestedModules,
+		filter:    filter,
+		deps:      *deps,
+		std:       *std,
+
+		assertions:        *assertions,
+		requireAssertions: *requireAssertions,
+		emitAssertions:    *emitAssertions,
+	}
+
+	switch {
+	case subject.Name != "":
+		err = runReverse(opts)
+	case multiple:
+		err = runInterfaces(specs, opts)
+	default:
+		err = runFinder(specs[0], opts)
+	}
+
+	if err != nil {
 		slog.Error("finder failed", "err", err)
 		os.Exit(1)
 	}
@@ -181,3 +181,79 @@ func TestMainHelp(t *testing.T) {
 		}
 	}
 }
+
+// not parallel: mutates os.Args/os.Stdout, changes the working directory and resets flag.CommandLine (global process state)
+func TestMainWithInterfaceList(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() { os.Args = oldArgs })
+
+	fixturesDir, err := filepath.Abs(".fixtures")
+	require.NoError(t, err)
+
+	list := filepath.Join(t.TempDir(), "interfaces.txt")
+	require.NoError(t, os.WriteFile(list, []byte("# fixtures\ninternal/app/app.go:App\nerror\n"), 0o644))
+
+	os.Args = []string{
+		"gofindimpl",
+		"-interfaces-from", list,
+		"-interface", "internal/app/app.go:App",
+		"./pkg/...",
+	}
+
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	t.Chdir(fixturesDir)
+
+	output, err := captureStdout(t, func() error {
+		main()
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	var byInterface map[string][]Implementation
+
+	require.NoError(t, json.Unmarshal([]byte(output), &byInterface))
+	require.Len(t, byInterface, 2)
+	assert.Empty(t, byInterface["error"])
+
+	found := make([]string, 0, len(byInterface["internal/app/app.go:App"]))
+	for _, impl := range byInterface["internal/app/app.go:App"] {
+		found = append(found, impl.Struct)
+	}
+
+	assert.ElementsMatch(t, []string{"WebServer", "ServiceDaemon", "MicroService"}, found)
+}
+
+// not parallel: mutates os.Args/os.Stdout, changes the working directory and resets flag.CommandLine (global process state)
+func TestMainWithRepeatedInterface(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() { os.Args = oldArgs })
+
+	fixturesDir, err := filepath.Abs(".fixtures")
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"gofindimpl",
+		"-interface", "internal/app/app.go:App",
+		"-interface", "internal/app/app.go:App",
+		"./pkg/...",
+	}
+
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	t.Chdir(fixturesDir)
+
+	output, err := captureStdout(t, func() error {
+		main()
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	var byInterface map[string][]Implementation
+
+	require.NoError(t, json.Unmarshal([]byte(output), &byInterface))
+	require.Len(t, byInterface, 1)
+	assert.Len(t, byInterface["internal/app/app.go:App"], 3)
+}
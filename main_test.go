@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,71 +19,127 @@ func TestParseInterfaceSpec(t *testing.T) {
 	testCases := []struct {
 		name          string
 		spec          string
-		expectedFile  string
-		expectedName  string
+		expected      interfaceSpec
 		expectedError bool
 	}{
 		{
-			name:          "valid spec",
-			spec:          "internal/app/server.go:Server",
-			expectedFile:  "internal/app/server.go",
-			expectedName:  "Server",
-			expectedError: false,
+			name:     "valid spec",
+			spec:     "internal/app/server.go:Server",
+			expected: interfaceSpec{File: "internal/app/server.go", Name: "Server"},
 		},
 		{
-			name:          "valid spec with spaces",
-			spec:          " internal/app/server.go : Server ",
-			expectedFile:  "internal/app/server.go",
-			expectedName:  "Server",
-			expectedError: false,
+			name:     "valid spec with spaces",
+			spec:     " internal/app/server.go : Server ",
+			expected: interfaceSpec{File: "internal/app/server.go", Name: "Server"},
+		},
+		{
+			name:     "stdlib import path",
+			spec:     "io.Writer",
+			expected: interfaceSpec{ImportPath: "io", Name: "Writer"},
+		},
+		{
+			name:     "nested stdlib import path",
+			spec:     "net/http.Handler",
+			expected: interfaceSpec{ImportPath: "net/http", Name: "Handler"},
+		},
+		{
+			name:     "module import path",
+			spec:     "github.com/our/lib/storage.Backend",
+			expected: interfaceSpec{ImportPath: "github.com/our/lib/storage", Name: "Backend"},
+		},
+		{
+			name:     "import path with dotted last element",
+			spec:     "gopkg.in/yaml.v3.Marshaler",
+			expected: interfaceSpec{ImportPath: "gopkg.in/yaml.v3", Name: "Marshaler"},
+		},
+		{
+			name:     "import path with colon",
+			spec:     "github.com/our/lib/storage:Backend",
+			expected: interfaceSpec{ImportPath: "github.com/our/lib/storage", Name: "Backend"},
+		},
+		{
+			name:     "relative directory with colon",
+			spec:     "./internal/app:Server",
+			expected: interfaceSpec{ImportPath: "./internal/app", Name: "Server"},
+		},
+		{
+			name:     "builtin error",
+			spec:     "error",
+			expected: interfaceSpec{Name: "error"},
+		},
+		{
+			name:     "generic file spec",
+			spec:     "internal/repo/repo.go:Repo[map[string]int]",
+			expected: interfaceSpec{File: "internal/repo/repo.go", Name: "Repo", TypeArgs: []string{"map[string]int"}},
+		},
+		{
+			name:     "generic import path spec",
+			spec:     "example.com/app/repo.KV[example.com/app/model.User, *]",
+			expected: interfaceSpec{ImportPath: "example.com/app/repo", Name: "KV", TypeArgs: []string{"example.com/app/model.User", "*"}},
+		},
+		{
+			name:     "generic spec with nested commas",
+			spec:     "example.com/app/repo:KV[func(int, string) error, *]",
+			expected: interfaceSpec{ImportPath: "example.com/app/repo", Name: "KV", TypeArgs: []string{"func(int, string) error", "*"}},
+		},
+		{
+			name:          "empty type argument list",
+			spec:          "example.com/app/repo.Repo[]",
+			expectedError: true,
+		},
+		{
+			name:          "unbalanced type argument list",
+			spec:          "example.com/app/repo.Repo]",
+			expectedError: true,
 		},
 		{
 			name:          "empty spec",
 			spec:          "",
-			expectedFile:  "",
-			expectedName:  "",
 			expectedError: true,
 		},
 		{
 			name:          "missing colon",
 			spec:          "internal/app/server.go",
-			expectedFile:  "",
-			expectedName:  "",
+			expectedError: true,
+		},
+		{
+			name:          "import path without name",
+			spec:          "github.com/our/lib/storage",
+			expectedError: true,
+		},
+		{
+			name:          "import path with trailing dot",
+			spec:          "io.",
+			expectedError: true,
+		},
+		{
+			name:          "import path with invalid name",
+			spec:          "io.1Writer",
 			expectedError: true,
 		},
 		{
 			name:          "too many colons",
 			spec:          "internal/app/server.go:Server:Extra",
-			expectedFile:  "",
-			expectedName:  "",
 			expectedError: true,
 		},
 		{
 			name:          "empty file path",
 			spec:          ":Server",
-			expectedFile:  "",
-			expectedName:  "",
 			expectedError: true,
 		},
 		{
 			name:          "empty interface name",
 			spec:          "internal/app/server.go:",
-			expectedFile:  "",
-			expectedName:  "",
 			expectedError: true,
 		},
 		{
 			name:          "only spaces in file path",
 			spec:          "   :Server",
-			expectedFile:  "",
-			expectedName:  "",
 			expectedError: true,
 		},
 		{
 			name:          "only spaces in interface name",
 			spec:          "internal/app/server.go:   ",
-			expectedFile:  "",
-			expectedName:  "",
 			expectedError: true,
 		},
 	}
@@ -89,7 +148,7 @@ func TestParseInterfaceSpec(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			file, name, err := parseInterfaceSpec(tc.spec)
+			spec, err := parseInterfaceSpec(tc.spec)
 
 			if tc.expectedError {
 				require.Error(t, err)
@@ -98,8 +157,51 @@ func TestParseInterfaceSpec(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			assert.Equal(t, tc.expectedFile, file)
-			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expected, spec)
+		})
+	}
+}
+
+func TestInterfaceSpecString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "app.go:App", interfaceSpec{File: "app.go", Name: "App"}.String())
+	assert.Equal(t, "io.Writer", interfaceSpec{ImportPath: "io", Name: "Writer"}.String())
+	assert.Equal(t, "error", interfaceSpec{Name: "error"}.String())
+	assert.Equal(t, "example.com/app/repo.KV[string, *]",
+		interfaceSpec{ImportPath: "example.com/app/repo", Name: "KV", TypeArgs: []string{"string", "*"}}.String())
+}
+
+func TestParseTypeSpec(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		spec          string
+		expected      typeSpec
+		expectedError bool
+	}{
+		{spec: "example.com/app/impl.Server", expected: typeSpec{ImportPath: "example.com/app/impl", Name: "Server"}},
+		{spec: "example.com/app/impl:Server", expected: typeSpec{ImportPath: "example.com/app/impl", Name: "Server"}},
+		{spec: "./internal/impl.Server", expected: typeSpec{ImportPath: "./internal/impl", Name: "Server"}},
+		{spec: "impl/server.go:Server", expectedError: true},
+		{spec: "Server", expectedError: true},
+		{spec: "error", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			t.Parallel()
+
+			spec, err := parseTypeSpec(tc.spec)
+
+			if tc.expectedError {
+				require.ErrorIs(t, err, ErrTypeSpecFormat)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, spec)
 		})
 	}
 }
@@ -165,13 +267,14 @@ func TestRunFinder(t *testing.T) {
 	})
 
 	testCases := []struct {
-		name          string
-		interfaceFile string
-		interfaceName string
-		searchDir     string
-		setup         func(t *testing.T) string // returns temp dir
-		expectedError bool
-		errorContains string
+		name                string
+		interfaceFile       string
+		interfaceImportPath string
+		interfaceName       string
+		searchDir           string
+		setup               func(t *testing.T) string // returns temp dir
+		expectedError       bool
+		errorContains       string
 	}{
 		{
 			name:          "validateArgs error - non-existent interface file",
@@ -330,6 +433,56 @@ func (t *TestStruct) Test() error { return nil }`
 			},
 			expectedError: false,
 		},
+		{
+			name:                "successful run with stdlib interface",
+			interfaceImportPath: "fmt",
+			interfaceName:       "Stringer",
+			searchDir:           ".",
+			setup: func(t *testing.T) string {
+				tempDir := t.TempDir()
+				err := os.WriteFile(tempDir+"/go.mod", []byte("module test.com/example\ngo 1.21"), 0o644)
+				require.NoError(t, err, "failed to create go.mod")
+				implContent := `package main
+type Name struct{}
+func (n Name) String() string { return "" }`
+				err = os.WriteFile(tempDir+"/impl.go", []byte(implContent), 0o644)
+				require.NoError(t, err, "failed to create impl file")
+				require.NoError(t, os.Chdir(tempDir))
+
+				return tempDir
+			},
+			expectedError: false,
+		},
+		{
+			name:          "successful run with builtin error",
+			interfaceName: "error",
+			searchDir:     ".",
+			setup: func(t *testing.T) string {
+				tempDir := t.TempDir()
+				err := os.WriteFile(tempDir+"/go.mod", []byte("module test.com/example\ngo 1.21"), 0o644)
+				require.NoError(t, err, "failed to create go.mod")
+				require.NoError(t, os.Chdir(tempDir))
+
+				return tempDir
+			},
+			expectedError: false,
+		},
+		{
+			name:                "unresolvable import path",
+			interfaceImportPath: "example.org/missing",
+			interfaceName:       "Store",
+			searchDir:           ".",
+			setup: func(t *testing.T) string {
+				tempDir := t.TempDir()
+				err := os.WriteFile(tempDir+"/go.mod", []byte("module test.com/example\ngo 1.21"), 0o644)
+				require.NoError(t, err, "failed to create go.mod")
+				require.NoError(t, os.Chdir(tempDir))
+
+				return tempDir
+			},
+			expectedError: true,
+			errorContains: "cannot resolve import",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -339,7 +492,13 @@ func (t *TestStruct) Test() error { return nil }`
 
 			tc.setup(t)
 
-			err := runFinder(tc.interfaceFile, tc.interfaceName, tc.searchDir)
+			spec := interfaceSpec{
+				File:       tc.interfaceFile,
+				ImportPath: tc.interfaceImportPath,
+				Name:       tc.interfaceName,
+			}
+
+			err := runFinder(spec, runOptions{searchDir: tc.searchDir})
 
 			if tc.expectedError {
 				require.Error(t, err)
@@ -355,3 +514,276 @@ func (t *TestStruct) Test() error { return nil }`
 		})
 	}
 }
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote
+// along with fn's error.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	output := make(chan []byte)
+
+	go func() {
+		content, _ := io.ReadAll(r)
+		output <- content
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	runErr := fn()
+
+	os.Stdout = stdout
+
+	require.NoError(t, w.Close())
+
+	return string(<-output), runErr
+}
+
+func TestRunReverse(t *testing.T) {
+	// not parallel: subtests change the working directory and os.Stdout
+
+	testCases := []struct {
+		name        string
+		opts        runOptions
+		expected    map[string]bool
+		expectedErr error
+	}{
+		{
+			name: "module interfaces",
+			opts: runOptions{
+				searchDir: ".",
+				subject:   typeSpec{ImportPath: "./store", Name: "File"},
+			},
+			expected: map[string]bool{
+				"example.com/app/app.Reader":     true,
+				"example.com/app/app.ReadCloser": true,
+				"example.com/app/app.Getter":     true,
+			},
+		},
+		{
+			name: "imported interfaces",
+			opts: runOptions{
+				searchDir: ".",
+				subject:   typeSpec{ImportPath: "./store", Name: "File"},
+				imported:  true,
+			},
+			expected: map[string]bool{
+				"example.com/app/app.Reader":     true,
+				"example.com/app/app.ReadCloser": true,
+				"example.com/app/app.Getter":     true,
+				"io.Reader":                      true,
+				"io.Closer":                      true,
+				"io.ReadCloser":                  true,
+			},
+		},
+		{
+			name: "unknown type",
+			opts: runOptions{
+				searchDir: ".",
+				subject:   typeSpec{ImportPath: "./store", Name: "Missing"},
+			},
+			expectedErr: ErrTypeNotFound,
+		},
+		{
+			name: "platforms",
+			opts: runOptions{
+				searchDir: ".",
+				subject:   typeSpec{ImportPath: "./store", Name: "File"},
+				platforms: []platform{{GOOS: "linux", GOARCH: "amd64"}},
+			},
+			expectedErr: ErrPlatformsWithType,
+		},
+		{
+			name: "with near miss",
+			opts: runOptions{
+				searchDir: ".",
+				subject:   typeSpec{ImportPath: "./store", Name: "File"},
+				nearMiss:  1,
+			},
+			expectedErr: ErrReverseConflict,
+		},
+		{
+			name: "missing search directory",
+			opts: runOptions{
+				searchDir: "missing",
+				subject:   typeSpec{ImportPath: "./store", Name: "File"},
+			},
+			expectedErr: ErrSearchDirNotExist,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Chdir(reverseModule(t))
+
+			output, err := captureStdout(t, func() error {
+				return runReverse(tc.opts)
+			})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				assert.Empty(t, output)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var matches []InterfaceMatch
+
+			require.NoError(t, json.Unmarshal([]byte(output), &matches))
+
+			found := make(map[string]bool, len(matches))
+			for _, match := range matches {
+				found[match.PackagePath+"."+match.Interface] = true
+			}
+
+			for name := range tc.expected {
+				assert.True(t, found[name], "missing %s", name)
+			}
+
+			if !tc.opts.imported {
+				assert.Equal(t, tc.expected, found)
+			}
+		})
+	}
+}
+
+func TestEmitAssertions(t *testing.T) {
+	// not parallel: changes the working directory and os.Stdout
+
+	root := newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Server interface{ Start() error }
+`,
+		"impl/impl.go": `package impl
+
+type Web struct{}
+
+func (Web) Start() error { return nil }
+`,
+	})
+
+	t.Chdir(root)
+
+	spec := interfaceSpec{File: filepath.Join("app", "app.go"), Name: "Server"}
+	assertionFile := filepath.Join(root, "impl", assertionFileName)
+
+	output, err := captureStdout(t, func() error {
+		return runFinder(spec, runOptions{searchDir: ".", emitAssertions: emitWrite})
+	})
+	require.NoError(t, err)
+
+	var implementations []Implementation
+
+	require.NoError(t, json.Unmarshal([]byte(output), &implementations))
+	require.Len(t, implementations, 1)
+	assert.Equal(t, "Web", implementations[0].Struct)
+
+	content, err := os.ReadFile(assertionFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "_ app.Server = Web{}")
+
+	// Once Web no longer implements Server, its assertion is stale and the
+	// file, left without lines, goes away.
+	writeTree(t, root, map[string]string{
+		"impl/impl.go": `package impl
+
+type Web struct{}
+
+func (Web) Start() int { return 0 }
+`,
+	})
+
+	output, err = captureStdout(t, func() error {
+		return runFinder(spec, runOptions{searchDir: ".", emitAssertions: emitDiff})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, output, "--- a/impl/"+assertionFileName+"\n+++ /dev/null\n")
+	assert.FileExists(t, assertionFile)
+
+	_, err = captureStdout(t, func() error {
+		return runFinder(spec, runOptions{searchDir: ".", emitAssertions: emitWrite})
+	})
+	require.NoError(t, err)
+	assert.NoFileExists(t, assertionFile)
+}
+
+func TestParseSearchSpecs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		interfaceArgs      []string
+		typeArg            string
+		expectedInterfaces []string
+		expectedType       string
+		expectedErr        error
+	}{
+		{
+			name:               "interface",
+			interfaceArgs:      []string{"io.Writer"},
+			expectedInterfaces: []string{"io.Writer"},
+		},
+		{
+			name:               "several interfaces",
+			interfaceArgs:      []string{"io.Writer", "./store/store.go:Store", "error"},
+			expectedInterfaces: []string{"io.Writer", "./store/store.go:Store", "error"},
+		},
+		{
+			name:               "duplicates searched once",
+			interfaceArgs:      []string{"io.Writer", "io:Writer", "error"},
+			expectedInterfaces: []string{"io.Writer", "error"},
+		},
+		{
+			name:          "invalid interface",
+			interfaceArgs: []string{"io.Writer", "nodot"},
+			expectedErr:   ErrInterfaceSpecFormat,
+		},
+		{
+			name:        "none",
+			expectedErr: ErrInterfaceSpecEmpty,
+		},
+		{
+			name:         "type",
+			typeArg:      "./store.File",
+			expectedType: "File",
+		},
+		{
+			name:          "both",
+			interfaceArgs: []string{"io.Writer"},
+			typeArg:       "./store.File",
+			expectedErr:   ErrTypeWithInterface,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			specs, subject, err := parseSearchSpecs(tc.interfaceArgs, tc.typeArg)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			interfaces := make([]string, 0, len(specs))
+			for _, spec := range specs {
+				interfaces = append(interfaces, spec.String())
+			}
+
+			if tc.expectedInterfaces == nil {
+				tc.expectedInterfaces = []string{}
+			}
+
+			assert.Equal(t, tc.expectedInterfaces, interfaces)
+			assert.Equal(t, tc.expectedType, subject.Name)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"go/types"
+	"strings"
+)
+
+// Reasons a method keeps a type from implementing the target interface.
+const (
+	reasonNotDeclared     = "not declared"
+	reasonSignature       = "signature differs"
+	reasonPointerReceiver = "pointer receiver"
+	reasonNameCase        = "name differs in case"
+	reasonOtherPackage    = "unexported method of another package"
+	reasonAmbiguous       = "ambiguous selector"
+)
+
+// MethodProblem describes one interface method that a near-miss type does
+// not provide as required. Expected is the interface's method and Actual the
+// closest method the type has, if any.
+type MethodProblem struct {
+	Method   string `json:"method"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// nearMissProblems returns what stands between namedType and an
+// implementation, split into methods it lacks entirely and methods it has in
+// the wrong shape.
+func (f *Finder) nearMissProblems(
+	namedType *types.Named,
+) ([]MethodProblem, []MethodProblem) {
+	var missing, mismatched []MethodProblem
+
+	for _, method := range f.explainMethods(namedType) {
+		problem := MethodProblem{
+			Method:   method.Method,
+			Reason:   method.Status,
+			Expected: method.Expected,
+			Actual:   method.Actual,
+		}
+
+		switch {
+		case method.Status == reasonNotDeclared:
+			missing = append(missing, problem)
+		case method.Status != statusOK:
+			mismatched = append(mismatched, problem)
+		case f.valueOnly && !method.InValueMethodSet:
+			problem.Reason = reasonPointerReceiver
+			mismatched = append(mismatched, problem)
+		}
+	}
+
+	return missing, mismatched
+}
+
+// isNearMiss reports whether a type with the given problems is worth
+// reporting: at most f.nearMiss problems, and at least one of the target's
+// methods present in some form, so unrelated types stay out of the report.
+func (f *Finder) isNearMiss(missing, mismatched []MethodProblem) bool {
+	problems := len(missing) + len(mismatched)
+
+	return problems > 0 &&
+		problems <= f.nearMiss &&
+		len(missing) < f.iface.NumMethods()
+}
+
+// similarMethod finds a method of methodSet that the programmer most likely
+// meant as method: one whose name only differs in case, or an unexported
+// method with the same name declared outside the interface's package.
+func similarMethod(methodSet *types.MethodSet, method *types.Func) *types.Func {
+	for selection := range methodSet.Methods() {
+		if !strings.EqualFold(selection.Obj().Name(), method.Name()) {
+			continue
+		}
+
+		if fn, ok := selection.Obj().(*types.Func); ok {
+			return fn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinder_NearMiss(t *testing.T) {
+	t.Parallel()
+
+	root := newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Service interface {
+	Start() error
+	Stop() error
+	Name() string
+	run()
+}
+`,
+		"impl/impl.go": `package impl
+
+type Complete struct{}
+
+func (Complete) Start() error { return nil }
+func (Complete) Stop() error  { return nil }
+func (Complete) Name() string { return "" }
+
+type OneMissing struct{}
+
+func (OneMissing) Start() error { return nil }
+func (OneMissing) Stop() error  { return nil }
+
+type WrongSignature struct{}
+
+func (WrongSignature) Start() error { return nil }
+func (WrongSignature) Stop() error  { return nil }
+func (WrongSignature) Name() []byte { return nil }
+
+type WrongCase struct{}
+
+func (WrongCase) Start() error { return nil }
+func (WrongCase) Stop() error  { return nil }
+func (WrongCase) name() string { return "" }
+
+type OwnRun struct{ Complete }
+
+func (OwnRun) run() {}
+
+type FarOff struct{}
+
+func (FarOff) Start() error { return nil }
+
+type Unrelated struct{}
+
+func (Unrelated) Close() error { return nil }
+`,
+	})
+
+	finder := newModuleFinder(t, root, "Service")
+	finder.nearMiss = 2
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Service"}))
+	require.NoError(t, finder.scanDirectory(filepath.Join(root, "impl")))
+
+	results := make(map[string]Implementation)
+	for _, result := range finder.getResults() {
+		results[result.Struct] = result
+	}
+
+	// Every type misses the unexported run, which a type outside the
+	// interface's package cannot declare.
+	notDeclared := MethodProblem{Method: "run", Reason: reasonNotDeclared, Expected: "run()"}
+
+	assert.Len(t, results, 5)
+	assert.NotContains(t, results, "FarOff")
+	assert.NotContains(t, results, "Unrelated")
+
+	assert.Equal(t, []MethodProblem{notDeclared}, results["Complete"].Missing)
+	assert.Empty(t, results["Complete"].Mismatched)
+	assert.False(t, results["Complete"].PointerImplements)
+
+	assert.ElementsMatch(t, []MethodProblem{
+		{Method: "Name", Reason: reasonNotDeclared, Expected: "Name() string"},
+		notDeclared,
+	}, results["OneMissing"].Missing)
+
+	assert.Equal(t, []MethodProblem{notDeclared}, results["WrongSignature"].Missing)
+	assert.Equal(t, []MethodProblem{{
+		Method:   "Name",
+		Reason:   reasonSignature,
+		Expected: "Name() string",
+		Actual:   "Name() []byte",
+	}}, results["WrongSignature"].Mismatched)
+
+	assert.Equal(t, []MethodProblem{{
+		Method:   "Name",
+		Reason:   reasonNameCase,
+		Expected: "Name() string",
+		Actual:   "name() string",
+	}}, results["WrongCase"].Mismatched)
+
+	assert.Empty(t, results["OwnRun"].Missing)
+	assert.Equal(t, []MethodProblem{{
+		Method:   "run",
+		Reason:   reasonOtherPackage,
+		Expected: "run()",
+		Actual:   "run()",
+	}}, results["OwnRun"].Mismatched)
+}
+
+func TestFinder_NearMissReasons(t *testing.T) {
+	t.Parallel()
+
+	pkg := checkSource(t, "test", `
+package test
+
+type Service interface {
+	Start() error
+	Stop() error
+}
+
+type Reader[T any] interface {
+	Read() (T, error)
+	Close() error
+}
+
+type PointerOnly struct{}
+
+func (*PointerOnly) Start() error { return nil }
+func (PointerOnly) Stop() error   { return nil }
+
+type genericReader[T any] struct{}
+
+func (genericReader[T]) Read() (T, error) {
+	var zero T
+	return zero, nil
+}
+func (genericReader[T]) Close() int { return 0 }
+`)
+
+	t.Run("pointer receiver with value only", func(t *testing.T) {
+		t.Parallel()
+
+		finder := NewFinder("Service")
+		finder.valueOnly = true
+		finder.nearMiss = 1
+		require.NoError(t, finder.setTarget(lookupNamed(t, pkg, "Service")))
+
+		missing, mismatched := finder.nearMissProblems(lookupNamed(t, pkg, "PointerOnly"))
+		assert.Empty(t, missing)
+		assert.Equal(t, []MethodProblem{{
+			Method:   "Start",
+			Reason:   reasonPointerReceiver,
+			Expected: "Start() error",
+			Actual:   "Start() error",
+		}}, mismatched)
+		assert.True(t, finder.isNearMiss(missing, mismatched))
+	})
+
+	t.Run("generic candidate", func(t *testing.T) {
+		t.Parallel()
+
+		finder := NewFinder("Reader")
+		finder.nearMiss = 1
+		require.NoError(t, finder.setTarget(lookupNamed(t, pkg, "Reader")))
+
+		missing, mismatched := finder.nearMissProblems(lookupNamed(t, pkg, "genericReader"))
+		assert.Empty(t, missing)
+		require.Len(t, mismatched, 1)
+		assert.Equal(t, "Close", mismatched[0].Method)
+		assert.Equal(t, reasonSignature, mismatched[0].Reason)
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		t.Parallel()
+
+		finder := NewFinder("Service")
+		finder.nearMiss = 1
+		require.NoError(t, finder.setTarget(lookupNamed(t, pkg, "Service")))
+
+		one := []MethodProblem{{Method: "Stop"}}
+		two := []MethodProblem{{Method: "Start"}, {Method: "Stop"}}
+
+		assert.True(t, finder.isNearMiss(one, nil))
+		assert.False(t, finder.isNearMiss(nil, nil))
+		assert.False(t, finder.isNearMiss(nil, two))
+		assert.False(t, finder.isNearMiss(two, nil))
+	})
+}
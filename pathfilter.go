@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// regexpPrefix marks an -exclude or -include pattern as a regular expression
+// rather than a glob.
+const regexpPrefix = "re:"
+
+// pathPattern is one -exclude or -include pattern.
+type pathPattern struct {
+	text string
+	glob string
+	re   *regexp.Regexp
+}
+
+func parsePathPattern(text string) (pathPattern, error) {
+	if expr, ok := strings.CutPrefix(text, regexpPrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return pathPattern{}, fmt.Errorf("%w %q: %w", ErrInvalidPathPattern, text, err)
+		}
+
+		return pathPattern{text: text, re: re}, nil
+	}
+
+	glob := strings.TrimSuffix(filepath.ToSlash(text), "/")
+	if _, err := path.Match(glob, ""); err != nil || glob == "" {
+		return pathPattern{}, fmt.Errorf("%w: %q", ErrInvalidPathPattern, text)
+	}
+
+	return pathPattern{text: text, glob: glob}, nil
+}
+
+// matches reports whether the pattern matches rel, a slash-separated path
+// relative to the module root. Globs match the whole path or just its last
+// element; regular expressions match anywhere in the path.
+func (p pathPattern) matches(rel string) bool {
+	if p.re != nil {
+		return p.re.MatchString(rel)
+	}
+
+	if matched, _ := path.Match(p.glob, rel); matched {
+		return true
+	}
+
+	matched, _ := path.Match(p.glob, path.Base(rel))
+
+	return matched
+}
+
+// pathFilter decides which directories the walk enters and which files' types
+// are reported, from -exclude and -include patterns and, optionally, the
+// .gitignore files from the module root down.
+type pathFilter struct {
+	excludes  []pathPattern
+	includes  []pathPattern
+	gitignore bool
+	ignores   map[string][]gitignoreRule
+}
+
+// newPathFilter parses the patterns into a filter.
+func newPathFilter(excludes, includes []string, gitignore bool) (*pathFilter, error) {
+	filter := &pathFilter{
+		gitignore: gitignore,
+		ignores:   make(map[string][]gitignoreRule),
+	}
+
+	for _, text := range excludes {
+		pattern, err := parsePathPattern(text)
+		if err != nil {
+			return nil, err
+		}
+
+		filter.excludes = append(filter.excludes, pattern)
+	}
+
+	for _, text := range includes {
+		pattern, err := parsePathPattern(text)
+		if err != nil {
+			return nil, err
+		}
+
+		filter.includes = append(filter.includes, pattern)
+	}
+
+	return filter, nil
+}
+
+func (pf *pathFilter) empty() bool {
+	return len(pf.excludes) == 0 && len(pf.includes) == 0 && !pf.gitignore
+}
+
+// pathExclusion tells why the walk or the results leave path out, or returns
+// "" when they do not. Excludes and .gitignore apply to directories and files,
+// each path element included; -include patterns only select files, matching
+// the file or any directory above it, so the walk still reaches them. Paths
+// outside every module are never filtered.
+func (f *Finder) pathExclusion(filePath string, isDir bool) string {
+	if f.filter == nil || f.filter.empty() {
+		return ""
+	}
+
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return ""
+	}
+
+	module := f.moduleForDir(abs)
+	if module == nil {
+		return ""
+	}
+
+	root, err := filepath.Abs(module.Root)
+	if err != nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == "." {
+		return ""
+	}
+
+	prefixes := pathPrefixes(filepath.ToSlash(rel))
+
+	for _, pattern := range f.filter.excludes {
+		for _, prefix := range prefixes {
+			if pattern.matches(prefix) {
+				return "-exclude " + pattern.text
+			}
+		}
+	}
+
+	if f.filter.gitignore && f.filter.gitignored(root, prefixes, isDir) {
+		return ".gitignore"
+	}
+
+	if isDir || len(f.filter.includes) == 0 {
+		return ""
+	}
+
+	for _, pattern := range f.filter.includes {
+		for _, prefix := range prefixes {
+			if pattern.matches(prefix) {
+				return ""
+			}
+		}
+	}
+
+	return "no -include match"
+}
+
+// allowsFile reports whether types declared in filename may be reported.
+func (f *Finder) allowsFile(filename string) bool {
+	reason := f.pathExclusion(filename, false)
+	if reason != "" {
+		slog.Debug("skipping types of filtered file", "file", filename, "reason", reason)
+	}
+
+	return reason == ""
+}
+
+// pathPrefixes lists a/b/c as a, a/b and a/b/c.
+func pathPrefixes(rel string) []string {
+	var prefixes []string
+
+	for i, c := range rel {
+		if c == '/' {
+			prefixes = append(prefixes, rel[:i])
+		}
+	}
+
+	return append(prefixes, rel)
+}
+
+// gitignoreRule is one pattern line of a .gitignore file.
+type gitignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// gitignored applies the .gitignore files from root down to each element of
+// the path in turn, the way git does: the last matching rule wins, and
+// nothing inside an ignored directory can be re-included.
+func (pf *pathFilter) gitignored(root string, prefixes []string, isDir bool) bool {
+	for i, prefix := range prefixes {
+		elemIsDir := isDir || i < len(prefixes)-1
+		ignored := false
+
+		dirs := append([]string{""}, prefixes[:i]...)
+		for _, dir := range dirs {
+			rel := strings.TrimPrefix(strings.TrimPrefix(prefix, dir), "/")
+
+			for _, rule := range pf.gitignoreRules(filepath.Join(root, filepath.FromSlash(dir))) {
+				if rule.dirOnly && !elemIsDir || !rule.re.MatchString(rel) {
+					continue
+				}
+
+				ignored = !rule.negate
+			}
+		}
+
+		if ignored {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gitignoreRules reads and caches the rules of dir's .gitignore, if any.
+func (pf *pathFilter) gitignoreRules(dir string) []gitignoreRule {
+	if rules, ok := pf.ignores[dir]; ok {
+		return rules
+	}
+
+	var rules []gitignoreRule
+
+	if content, err := os.ReadFile(filepath.Join(dir, ".gitignore")); err == nil {
+		rules = parseGitignore(string(content))
+	}
+
+	pf.ignores[dir] = rules
+
+	return rules
+}
+
+// parseGitignore turns .gitignore lines into rules.
+func parseGitignore(content string) []gitignoreRule {
+	var rules []gitignoreRule
+
+	for line := range strings.SplitSeq(content, "\n") {
+		if rule, ok := parseGitignoreLine(line); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// parseGitignoreLine parses one pattern line. Patterns with a slash other
+// than a trailing one are anchored to the .gitignore's directory, others
+// match at any depth.
+func parseGitignoreLine(line string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	var rule gitignoreRule
+
+	line, rule.negate = strings.CutPrefix(line, "!")
+	line, rule.dirOnly = strings.CutSuffix(line, "/")
+
+	anchored := strings.Contains(line, "/")
+
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+
+	expr := "^" + gitignoreRegexp(line) + "$"
+	if !anchored {
+		expr = "^(.*/)?" + gitignoreRegexp(line) + "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		slog.Debug("skipping invalid .gitignore pattern", "pattern", line, "err", err)
+
+		return gitignoreRule{}, false
+	}
+
+	rule.re = re
+
+	return rule, true
+}
+
+// gitignoreRegexp translates a .gitignore glob into a regular expression:
+// "**" spans directories, "*" and "?" stay within one, and character classes
+// are kept.
+func gitignoreRegexp(glob string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case glob[i:] == "/**":
+			sb.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			class, n := gitignoreClass(glob[i:])
+			sb.WriteString(class)
+			i += n - 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
+
+// gitignoreClass translates the character class glob starts with and returns
+// it along with the number of bytes it spans. An unclosed "[" is literal.
+func gitignoreClass(glob string) (string, int) {
+	end := strings.IndexByte(glob[1:], ']')
+	if end < 0 {
+		return `\[`, 1
+	}
+
+	class := glob[1 : 1+end]
+	if rest, ok := strings.CutPrefix(class, "!"); ok {
+		class = "^" + rest
+	}
+
+	return "[" + class + "]", end + 2
+}
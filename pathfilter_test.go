@@ -0,0 +1,195 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := parsePathPattern("generated")
+	require.NoError(t, err)
+
+	_, err = parsePathPattern("re:_gen\\.go$")
+	require.NoError(t, err)
+
+	_, err = parsePathPattern("[")
+	require.ErrorIs(t, err, ErrInvalidPathPattern)
+
+	_, err = parsePathPattern("re:(")
+	require.ErrorIs(t, err, ErrInvalidPathPattern)
+}
+
+func TestPathPatternMatches(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{pattern: "generated", rel: "generated", want: true},
+		{pattern: "generated", rel: "internal/generated", want: true},
+		{pattern: "generated/", rel: "internal/generated", want: true},
+		{pattern: "*_gen.go", rel: "internal/store/store_gen.go", want: true},
+		{pattern: "internal/*", rel: "internal/store", want: true},
+		{pattern: "internal/*", rel: "cmd/internal", want: false},
+		{pattern: "re:^third_party/", rel: "third_party/lib/lib.go", want: true},
+		{pattern: "re:^third_party/", rel: "internal/third_party/lib.go", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.rel, func(t *testing.T) {
+			t.Parallel()
+
+			pattern, err := parsePathPattern(tc.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, pattern.matches(tc.rel))
+		})
+	}
+}
+
+func TestParseGitignore(t *testing.T) {
+	t.Parallel()
+
+	rules := parseGitignore(`# build output
+/bin
+*.pb.go
+!keep.pb.go
+docs/**/gen
+tmp/
+\#literal
+`)
+
+	testCases := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{rel: "bin", isDir: true, want: true},
+		{rel: "cmd/bin", isDir: true, want: false},
+		{rel: "api/api.pb.go", want: true},
+		{rel: "api/keep.pb.go", want: false},
+		{rel: "docs/a/b/gen", isDir: true, want: true},
+		{rel: "docs/gen", isDir: true, want: true},
+		{rel: "tmp", isDir: true, want: true},
+		{rel: "tmp", want: false},
+		{rel: "#literal", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rel, func(t *testing.T) {
+			t.Parallel()
+
+			ignored := false
+
+			for _, rule := range rules {
+				if rule.dirOnly && !tc.isDir || !rule.re.MatchString(tc.rel) {
+					continue
+				}
+
+				ignored = !rule.negate
+			}
+
+			assert.Equal(t, tc.want, ignored)
+		})
+	}
+}
+
+func TestFinder_PathFilter(t *testing.T) {
+	t.Parallel()
+
+	impl := func(pkg, name string) string {
+		return "package " + pkg + "\n\ntype " + name + ` struct{}
+
+func (` + name + `) Start() error { return nil }
+`
+	}
+
+	tree := map[string]string{
+		".gitignore": "/build\n*_mock.go\n!keep_mock.go\n",
+		"app/app.go": `package app
+
+type Server interface{ Start() error }
+`,
+		"internal/web/web.go":           impl("web", "Web"),
+		"internal/web/web_mock.go":      impl("web", "WebMock"),
+		"internal/web/keep_mock.go":     impl("web", "KeepMock"),
+		"internal/store/store_gen.go":   impl("store", "StoreGen"),
+		"internal/generated/gen/gen.go": impl("gen", "Gen"),
+		"build/out/out.go":              impl("out", "Out"),
+		"examples/demo/demo.go":         impl("demo", "Demo"),
+	}
+
+	testCases := []struct {
+		name      string
+		excludes  []string
+		includes  []string
+		gitignore bool
+		want      []string
+	}{
+		{
+			name: "no filters",
+			want: []string{"Demo", "Gen", "KeepMock", "Out", "StoreGen", "Web", "WebMock"},
+		},
+		{
+			name:     "excluded directories and files",
+			excludes: []string{"generated", "examples/", "*_gen.go"},
+			want:     []string{"KeepMock", "Out", "Web", "WebMock"},
+		},
+		{
+			name:     "excluded by regexp",
+			excludes: []string{"re:(^build|_mock\\.go$)"},
+			want:     []string{"Demo", "Gen", "StoreGen", "Web"},
+		},
+		{
+			name:     "included directories",
+			includes: []string{"internal/web", "examples"},
+			want:     []string{"Demo", "KeepMock", "Web", "WebMock"},
+		},
+		{
+			name:     "include and exclude together",
+			includes: []string{"internal"},
+			excludes: []string{"*_mock.go"},
+			want:     []string{"Gen", "StoreGen", "Web"},
+		},
+		{
+			name:      "gitignore",
+			gitignore: true,
+			want:      []string{"Demo", "Gen", "KeepMock", "StoreGen", "Web"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := newTempModule(t, tree)
+
+			filter, err := newPathFilter(tc.excludes, tc.includes, tc.gitignore)
+			require.NoError(t, err)
+
+			finder := newModuleFinder(t, root, "Server")
+			finder.filter = filter
+
+			require.NoError(t, finder.loadInterface(interfaceSpec{
+				File: filepath.Join(root, "app", "app.go"),
+				Name: "Server",
+			}))
+			require.NoError(t, finder.scanDirectory(root))
+
+			var got []string
+			for _, impl := range finder.getResults() {
+				got = append(got, impl.Struct)
+			}
+
+			slices.Sort(got)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
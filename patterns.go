@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// packagePattern is a package pattern resolved to the directory holding its
+// packages. A recursive pattern also covers the directories below dir, those
+// accepted by match when it is set.
+type packagePattern struct {
+	dir       string
+	recursive bool
+	match     func(dir string) bool
+}
+
+// scanPatterns scans the packages matching Go package patterns the way go
+// build takes them: directories such as ./internal/... or import paths such
+// as example.com/mod/pkg/..., the latter resolved against the module or the
+// workspace.
+func (f *Finder) scanPatterns(patterns []string) error {
+	resolved := make([]packagePattern, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		packagePatterns, err := f.resolvePattern(pattern)
+		if err != nil {
+			return err
+		}
+
+		resolved = append(resolved, packagePatterns...)
+	}
+
+	return f.scanPackagePatterns(resolved)
+}
+
+func (f *Finder) resolvePattern(pattern string) ([]packagePattern, error) {
+	if isLocalPattern(pattern) {
+		return resolveDirPattern(pattern)
+	}
+
+	return f.resolveImportPattern(pattern)
+}
+
+// isLocalPattern reports whether pattern names directories rather than
+// import paths: relative to the working directory with a leading ./ or ../,
+// as the go command requires, or absolute.
+func isLocalPattern(pattern string) bool {
+	return pattern == "." || pattern == ".." ||
+		strings.HasPrefix(pattern, "./") || strings.HasPrefix(pattern, "../") ||
+		filepath.IsAbs(pattern)
+}
+
+func resolveDirPattern(pattern string) ([]packagePattern, error) {
+	abs, err := filepath.Abs(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve pattern %s: %w", pattern, err)
+	}
+
+	prefix, wildcard := literalPrefix(filepath.ToSlash(abs))
+	dir := filepath.FromSlash(prefix)
+
+	if !isDir(dir) {
+		return nil, fmt.Errorf("%w: %s", ErrSearchDirNotExist, pattern)
+	}
+
+	if !wildcard {
+		return []packagePattern{{dir: dir}}, nil
+	}
+
+	matchPath := matchPattern(filepath.ToSlash(abs))
+
+	return []packagePattern{{
+		dir:       dir,
+		recursive: true,
+		match: func(dir string) bool {
+			abs, err := filepath.Abs(dir)
+
+			return err == nil && matchPath(filepath.ToSlash(abs))
+		},
+	}}, nil
+}
+
+// resolveImportPattern finds the directories of the modules an import path
+// pattern can match in.
+func (f *Finder) resolveImportPattern(pattern string) ([]packagePattern, error) {
+	prefix, wildcard := literalPrefix(pattern)
+	matchPath := matchPattern(pattern)
+	match := func(dir string) bool {
+		return matchPath(f.importPathForDir(dir))
+	}
+
+	var resolved []packagePattern
+
+	for _, module := range f.workspaceModules() {
+		var dir string
+
+		switch {
+		case hasPathPrefix(prefix, module.Path):
+			rel := strings.TrimPrefix(strings.TrimPrefix(prefix, module.Path), "/")
+			dir = filepath.Join(module.Root, filepath.FromSlash(rel))
+		case wildcard && (prefix == "" || hasPathPrefix(module.Path, prefix)):
+			dir = module.Root
+		default:
+			continue
+		}
+
+		if isDir(dir) {
+			resolved = append(resolved, packagePattern{
+				dir:       dir,
+				recursive: wildcard,
+				match:     match,
+			})
+		}
+	}
+
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPatternNoModule, pattern)
+	}
+
+	return resolved, nil
+}
+
+// literalPrefix returns the directory part of pattern before its first
+// "..." wildcard, and whether there is one.
+func literalPrefix(pattern string) (string, bool) {
+	i := strings.Index(pattern, "...")
+	if i < 0 {
+		return pattern, false
+	}
+
+	prefix := pattern[:i]
+
+	j := strings.LastIndex(prefix, "/")
+	switch {
+	case j > 0:
+		return prefix[:j], true
+	case j == 0:
+		return "/", true
+	default:
+		return "", true
+	}
+}
+
+// matchPattern returns a function reporting whether a slash-separated path
+// matches pattern, where "..." matches any string and a trailing "/..." also
+// matches the empty string, so net/... matches net itself.
+func matchPattern(pattern string) func(path string) bool {
+	re := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\.\.\.`, `.*`)
+	if strings.HasSuffix(re, `/.*`) {
+		re = strings.TrimSuffix(re, `/.*`) + `(/.*)?`
+	}
+
+	return regexp.MustCompile(`^` + re + `$`).MatchString
+}
+
+// isIgnoredDir reports whether the go command leaves the directory out when
+// matching "..." patterns: testdata and names starting with "." or "_".
+func isIgnoredDir(name string) bool {
+	return name == "testdata" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchPattern(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "net/...", path: "net", want: true},
+		{pattern: "net/...", path: "net/http", want: true},
+		{pattern: "net/...", path: "netchan", want: false},
+		{pattern: "net...", path: "netchan", want: true},
+		{pattern: "example.com/.../store", path: "example.com/app/store", want: true},
+		{pattern: "example.com/.../store", path: "example.com/app/store/sql", want: false},
+		{pattern: "example.com/app", path: "example.com/app", want: true},
+		{pattern: "example.com/app", path: "example.com/app/store", want: false},
+		{pattern: "...", path: "anything/at/all", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, matchPattern(tc.pattern)(tc.path))
+		})
+	}
+}
+
+func TestLiteralPrefix(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern      string
+		wantPrefix   string
+		wantWildcard bool
+	}{
+		{pattern: "example.com/app/...", wantPrefix: "example.com/app", wantWildcard: true},
+		{pattern: "example.com/app/int...", wantPrefix: "example.com/app", wantWildcard: true},
+		{pattern: "example.com/app", wantPrefix: "example.com/app"},
+		{pattern: "...", wantPrefix: "", wantWildcard: true},
+		{pattern: "/...", wantPrefix: "/", wantWildcard: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			t.Parallel()
+
+			prefix, wildcard := literalPrefix(tc.pattern)
+			assert.Equal(t, tc.wantPrefix, prefix)
+			assert.Equal(t, tc.wantWildcard, wildcard)
+		})
+	}
+}
+
+func TestIsLocalPattern(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isLocalPattern("."))
+	assert.True(t, isLocalPattern("./..."))
+	assert.True(t, isLocalPattern("../other/..."))
+	assert.True(t, isLocalPattern("/abs/..."))
+	assert.False(t, isLocalPattern("example.com/app/..."))
+	assert.False(t, isLocalPattern("internal/..."))
+}
+
+func TestFinder_ScanPatterns(t *testing.T) {
+	t.Parallel()
+
+	tree := map[string]string{
+		"app/app.go": `package app
+
+type Server interface{ Start() error }
+`,
+	}
+
+	for _, dir := range []string{
+		"internal/web", "internal/grpc", "cmd/tool",
+		"internal/testdata/fake", "internal/_old", "internal/.cache",
+	} {
+		name := filepath.Base(dir)
+		tree[dir+"/"+name+".go"] = "package " + name + `
+
+type Impl struct{}
+
+func (Impl) Start() error { return nil }
+`
+	}
+
+	testCases := []struct {
+		name        string
+		patterns    []string
+		want        []string
+		expectedErr error
+	}{
+		{
+			name:     "import path pattern",
+			patterns: []string{"example.com/app/internal/..."},
+			want:     []string{"example.com/app/internal/grpc", "example.com/app/internal/web"},
+		},
+		{
+			name:     "directory pattern",
+			patterns: []string{"{root}/internal/..."},
+			want:     []string{"example.com/app/internal/grpc", "example.com/app/internal/web"},
+		},
+		{
+			name:     "overlapping patterns are scanned once",
+			patterns: []string{"{root}/...", "{root}/internal/web", "example.com/app/cmd/..."},
+			want: []string{
+				"example.com/app/cmd/tool",
+				"example.com/app/internal/grpc",
+				"example.com/app/internal/web",
+			},
+		},
+		{
+			name:     "wildcard inside the pattern",
+			patterns: []string{"example.com/.../web"},
+			want:     []string{"example.com/app/internal/web"},
+		},
+		{
+			name:     "ignored directory named explicitly",
+			patterns: []string{"{root}/internal/testdata/fake"},
+			want:     []string{"example.com/app/internal/testdata/fake"},
+		},
+		{
+			name:        "import path outside the module",
+			patterns:    []string{"example.com/other/..."},
+			expectedErr: ErrPatternNoModule,
+		},
+		{
+			name:        "missing directory",
+			patterns:    []string{"{root}/missing/..."},
+			expectedErr: ErrSearchDirNotExist,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := newTempModule(t, tree)
+
+			finder := newModuleFinder(t, root, "Server")
+			require.NoError(t, finder.loadInterface(interfaceSpec{
+				File: filepath.Join(root, "app", "app.go"),
+				Name: "Server",
+			}))
+
+			// Directory patterns are absolute here: relative ones are
+			// resolved against the working directory.
+			patterns := make([]string, 0, len(tc.patterns))
+			for _, pattern := range tc.patterns {
+				patterns = append(patterns, strings.ReplaceAll(pattern, "{root}", root))
+			}
+
+			err := finder.scanPatterns(patterns)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var got []string
+			for _, impl := range finder.getResults() {
+				got = append(got, impl.PackagePath)
+			}
+
+			slices.Sort(got)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// platform is a GOOS/GOARCH pair to select files for. Empty fields keep the
+// host's value.
+type platform struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (p platform) String() string {
+	return p.GOOS + "/" + p.GOARCH
+}
+
+// parsePlatforms reads a comma-separated list of GOOS/GOARCH pairs.
+func parsePlatforms(value string) ([]platform, error) {
+	var platforms []platform
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		goos, goarch, ok := strings.Cut(entry, "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return nil, fmt.Errorf("%w: %q", ErrPlatformFormat, entry)
+		}
+
+		platforms = append(platforms, platform{GOOS: goos, GOARCH: goarch})
+	}
+
+	return platforms, nil
+}
+
+// parseTags splits a -tags value the way go build does: on commas, or on
+// spaces for the older form.
+func parseTags(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// mergePlatformResults unions the implementations found for each platform,
+// in order of first appearance, and records on which platforms each type
+// implements the interface. Types are matched by package path, test package
+// and name, and only merge when they implement the interface the same way on
+// each platform: a type whose receivers or near-miss problems differ gets an
+// entry per variant, each listing its own platforms. The other details of
+// the first platform a variant was found on are kept.
+func mergePlatformResults(
+	platforms []platform, perPlatform [][]Implementation,
+) []Implementation {
+	merged := make([]Implementation, 0)
+	index := make(map[string]int)
+
+	for i, implementations := range perPlatform {
+		for _, impl := range implementations {
+			key := platformMergeKey(impl)
+
+			pos, ok := index[key]
+			if !ok {
+				pos = len(merged)
+				index[key] = pos
+				merged = append(merged, impl)
+			}
+
+			merged[pos].Platforms = append(merged[pos].Platforms, platforms[i].String())
+		}
+	}
+
+	return merged
+}
+
+// platformMergeKey identifies a type along with how it implements the
+// interface, so that only identical findings merge across platforms.
+func platformMergeKey(impl Implementation) string {
+	return fmt.Sprintf("%s.%s|%s|%t|%t|%v|%v",
+		impl.PackagePath, impl.Struct, impl.TestPackage,
+		impl.ValueImplements, impl.PointerImplements, impl.Missing, impl.Mismatched)
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func platformsModule(t *testing.T) string {
+	t.Helper()
+
+	return newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Closer interface {
+	Close() error
+}
+`,
+		"impl/common.go": `package impl
+
+type Common struct{}
+
+func (Common) Close() error { return nil }
+`,
+		"impl/server_linux.go": `package impl
+
+type LinuxServer struct{}
+
+func (LinuxServer) Close() error { return nil }
+`,
+		"impl/server_windows.go": `package impl
+
+type WindowsServer struct{}
+
+func (WindowsServer) Close() error { return nil }
+`,
+		"impl/handle_unix.go": `//go:build unix
+
+package impl
+
+type Handle struct{ fd int }
+
+func (Handle) Close() error { return nil }
+`,
+		"impl/handle_other.go": `//go:build !unix
+
+package impl
+
+type Handle struct{ h uintptr }
+
+func (*Handle) Close() error { return nil }
+`,
+		"impl/enterprise.go": `//go:build enterprise
+
+package impl
+
+type Audit struct{}
+
+func (Audit) Close() error { return nil }
+`,
+	})
+}
+
+func TestFinder_BuildConstraints(t *testing.T) {
+	t.Parallel()
+
+	root := platformsModule(t)
+
+	testCases := []struct {
+		name     string
+		goos     string
+		goarch   string
+		tags     []string
+		expected []string
+	}{
+		{
+			name:     "linux",
+			goos:     "linux",
+			goarch:   "amd64",
+			expected: []string{"Common", "Handle", "LinuxServer"},
+		},
+		{
+			name:     "windows",
+			goos:     "windows",
+			goarch:   "amd64",
+			expected: []string{"Common", "Handle", "WindowsServer"},
+		},
+		{
+			name:     "build tag",
+			goos:     "darwin",
+			goarch:   "arm64",
+			tags:     []string{"enterprise"},
+			expected: []string{"Audit", "Common", "Handle"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, "Closer")
+			finder.setBuildTarget(tc.goos, tc.goarch, tc.tags)
+			require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Closer"}))
+			require.NoError(t, finder.scanDirectory(filepath.Join(root, "impl")))
+
+			found := make([]string, 0)
+			for _, result := range finder.getResults() {
+				found = append(found, result.Struct)
+			}
+
+			assert.ElementsMatch(t, tc.expected, found)
+		})
+	}
+}
+
+func TestFinder_BuildConstraintsPickOneDeclaration(t *testing.T) {
+	t.Parallel()
+
+	root := platformsModule(t)
+
+	finder := newModuleFinder(t, root, "Closer")
+	finder.setBuildTarget("windows", "amd64", nil)
+	require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Closer"}))
+	require.NoError(t, finder.scanDirectory(filepath.Join(root, "impl")))
+
+	// Only handle_other.go is selected, so Handle has its pointer receiver
+	// instead of a duplicate declaration.
+	for _, result := range finder.getResults() {
+		if result.Struct == "Handle" {
+			assert.False(t, result.ValueImplements)
+			assert.True(t, result.PointerImplements)
+		}
+	}
+}
+
+func TestParsePlatforms(t *testing.T) {
+	t.Parallel()
+
+	platforms, err := parsePlatforms("linux/amd64, windows/arm64,")
+	require.NoError(t, err)
+	assert.Equal(t, []platform{
+		{GOOS: "linux", GOARCH: "amd64"},
+		{GOOS: "windows", GOARCH: "arm64"},
+	}, platforms)
+
+	platforms, err = parsePlatforms("")
+	require.NoError(t, err)
+	assert.Empty(t, platforms)
+
+	for _, invalid := range []string{"linux", "linux/", "/amd64", "linux/amd64/v3"} {
+		_, err := parsePlatforms(invalid)
+		require.ErrorIs(t, err, ErrPlatformFormat, invalid)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"enterprise", "debug"}, parseTags("enterprise,debug"))
+	assert.Equal(t, []string{"enterprise", "debug"}, parseTags("enterprise debug"))
+	assert.Empty(t, parseTags(""))
+}
+
+func TestMergePlatformResults(t *testing.T) {
+	t.Parallel()
+
+	linux := platform{GOOS: "linux", GOARCH: "amd64"}
+	windows := platform{GOOS: "windows", GOARCH: "amd64"}
+
+	merged := mergePlatformResults(
+		[]platform{linux, windows},
+		[][]Implementation{
+			{
+				{Struct: "Common", PackagePath: "example.com/app/impl"},
+				{Struct: "LinuxServer", PackagePath: "example.com/app/impl"},
+			},
+			{
+				{Struct: "Common", PackagePath: "example.com/app/impl"},
+				{Struct: "WindowsServer", PackagePath: "example.com/app/impl"},
+				{Struct: "Common", PackagePath: "example.com/app/other"},
+			},
+		},
+	)
+
+	assert.Equal(t, []Implementation{
+		{Struct: "Common", PackagePath: "example.com/app/impl", Platforms: []string{"linux/amd64", "windows/amd64"}},
+		{Struct: "LinuxServer", PackagePath: "example.com/app/impl", Platforms: []string{"linux/amd64"}},
+		{Struct: "WindowsServer", PackagePath: "example.com/app/impl", Platforms: []string{"windows/amd64"}},
+		{Struct: "Common", PackagePath: "example.com/app/other", Platforms: []string{"windows/amd64"}},
+	}, merged)
+}
+
+func TestMergePlatformResultsDiffering(t *testing.T) {
+	t.Parallel()
+
+	linux := platform{GOOS: "linux", GOARCH: "amd64"}
+	windows := platform{GOOS: "windows", GOARCH: "amd64"}
+	mismatched := []MethodProblem{{Method: "Close", Reason: "signature", Expected: "func() error", Actual: "func()"}}
+
+	testCases := []struct {
+		name     string
+		linux    []Implementation
+		windows  []Implementation
+		expected []Implementation
+	}{
+		{
+			name: "differing receivers",
+			linux: []Implementation{
+				{Struct: "Handle", PackagePath: "example.com/app/impl", ValueImplements: true, PointerImplements: true},
+			},
+			windows: []Implementation{
+				{Struct: "Handle", PackagePath: "example.com/app/impl", PointerImplements: true},
+			},
+			expected: []Implementation{
+				{
+					Struct: "Handle", PackagePath: "example.com/app/impl",
+					ValueImplements: true, PointerImplements: true, Platforms: []string{"linux/amd64"},
+				},
+				{
+					Struct: "Handle", PackagePath: "example.com/app/impl",
+					PointerImplements: true, Platforms: []string{"windows/amd64"},
+				},
+			},
+		},
+		{
+			name: "near miss on one platform",
+			linux: []Implementation{
+				{Struct: "Handle", PackagePath: "example.com/app/impl", Mismatched: mismatched},
+			},
+			windows: []Implementation{
+				{Struct: "Handle", PackagePath: "example.com/app/impl", ValueImplements: true, PointerImplements: true},
+			},
+			expected: []Implementation{
+				{
+					Struct: "Handle", PackagePath: "example.com/app/impl",
+					Mismatched: mismatched, Platforms: []string{"linux/amd64"},
+				},
+				{
+					Struct: "Handle", PackagePath: "example.com/app/impl",
+					ValueImplements: true, PointerImplements: true, Platforms: []string{"windows/amd64"},
+				},
+			},
+		},
+		{
+			name: "external test package",
+			linux: []Implementation{
+				{Struct: "Fake", PackagePath: "example.com/app/impl", PointerImplements: true},
+				{Struct: "Fake", PackagePath: "example.com/app/impl", TestPackage: "impl_test", PointerImplements: true},
+			},
+			windows: []Implementation{
+				{Struct: "Fake", PackagePath: "example.com/app/impl", TestPackage: "impl_test", PointerImplements: true},
+			},
+			expected: []Implementation{
+				{
+					Struct: "Fake", PackagePath: "example.com/app/impl",
+					PointerImplements: true, Platforms: []string{"linux/amd64"},
+				},
+				{
+					Struct: "Fake", PackagePath: "example.com/app/impl", TestPackage: "impl_test",
+					PointerImplements: true, Platforms: []string{"linux/amd64", "windows/amd64"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			merged := mergePlatformResults(
+				[]platform{linux, windows},
+				[][]Implementation{tc.linux, tc.windows},
+			)
+
+			assert.Equal(t, tc.expected, merged)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// InterfaceMatch is one interface the -type subject implements.
+type InterfaceMatch struct {
+	Package           string   `json:"package"`
+	Interface         string   `json:"interface"`
+	PackagePath       string   `json:"packagePath"`
+	ValueImplements   bool     `json:"valueImplements"`
+	PointerImplements bool     `json:"pointerImplements"`
+	TypeArgs          []string `json:"typeArgs,omitempty"`
+	InterfaceTypeArgs []string `json:"interfaceTypeArgs,omitempty"`
+}
+
+// loadSubject loads the type whose interfaces -type looks for.
+func (f *Finder) loadSubject(spec typeSpec) error {
+	pkg, err := f.importPackage(spec.ImportPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load package %s: %w",
+			spec.ImportPath,
+			err,
+		)
+	}
+
+	typeName, ok := pkg.Scope().Lookup(spec.Name).(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("%w '%s' in %s", ErrTypeNotFound, spec.Name, spec.ImportPath)
+	}
+
+	named, ok := types.Unalias(typeName.Type()).(*types.Named)
+	if !ok {
+		return fmt.Errorf("%w '%s' in %s", ErrTypeNotFound, spec.Name, spec.ImportPath)
+	}
+
+	f.subject = named
+	f.checkedPackages = make(map[*types.Package]bool)
+
+	return nil
+}
+
+// findInterfacesInTypedPackage checks the subject against every interface
+// declared in pkg.
+func (f *Finder) findInterfacesInTypedPackage(pkg *types.Package) {
+	f.checkInterfaces(pkg, false)
+}
+
+// checkInterfaces does the work of findInterfacesInTypedPackage, optionally
+// skipping unexported interfaces. Each package is checked once, whether it
+// was reached by the directory walk or as an import.
+func (f *Finder) checkInterfaces(pkg *types.Package, exportedOnly bool) {
+	if f.checkedPackages[pkg] {
+		return
+	}
+
+	f.checkedPackages[pkg] = true
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || typeName.IsAlias() || exportedOnly && !typeName.Exported() ||
+			!f.allowsFile(f.fset.Position(typeName.Pos()).Filename) {
+			continue
+		}
+
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || named.Origin() == f.subject.Origin() {
+			continue
+		}
+
+		iface, ok := named.Underlying().(*types.Interface)
+		if !ok || iface.Empty() || !iface.IsMethodSet() {
+			continue
+		}
+
+		match, ok := f.matchSubject(named)
+		if !ok {
+			continue
+		}
+
+		f.interfaceMatches = append(f.interfaceMatches, InterfaceMatch{
+			Package:           pkg.Name(),
+			Interface:         typeName.Name(),
+			PackagePath:       pkg.Path(),
+			ValueImplements:   match.valueImplements,
+			PointerImplements: match.pointerImplements,
+			TypeArgs:          typeStrings(match.typeArgs),
+			InterfaceTypeArgs: typeStrings(match.interfaceTypeArgs),
+		})
+	}
+}
+
+// matchSubject makes named the target, generic ones with wildcard type
+// arguments, and matches the subject against it with the same logic the
+// forward search uses.
+func (f *Finder) matchSubject(named *types.Named) (typeMatch, bool) {
+	f.typeArgSpecs = nil
+	f.ifaceGeneric = nil
+	f.ifaceTypeArgs = nil
+
+	if err := f.setTarget(named); err != nil {
+		slog.Debug("skipping interface", "interface", named.Obj().Name(), "err", err)
+
+		return typeMatch{}, false
+	}
+
+	return f.matchType(f.subject)
+}
+
+// scanImportedInterfaces extends the search to every package loaded while
+// scanning: the standard library and dependencies the module imports,
+// directly or not. Their unexported interfaces are left out since code
+// outside those packages cannot name them.
+func (f *Finder) scanImportedInterfaces() {
+	paths := make([]string, 0, len(f.packages))
+	for path, pkg := range f.packages {
+		if pkg != nil && !strings.HasPrefix(path, "vendor/") {
+			paths = append(paths, path)
+		}
+	}
+
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		f.checkInterfaces(f.packages[path], true)
+	}
+}
+
+func (f *Finder) getInterfaceMatches() []InterfaceMatch {
+	return f.interfaceMatches
+}
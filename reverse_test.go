@@ -0,0 +1,176 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reverseModule(t *testing.T) string {
+	t.Helper()
+
+	return newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Reader interface {
+	Read(p []byte) (int, error)
+}
+
+type ReadCloser interface {
+	Reader
+	Close() error
+}
+
+type Getter[T any] interface {
+	Get() T
+}
+
+type Writer interface {
+	Write(p []byte) (int, error)
+}
+
+type Any interface{}
+`,
+		"store/store.go": `package store
+
+import "io"
+
+type File struct{}
+
+func (File) Read(p []byte) (int, error) { return 0, nil }
+
+func (*File) Close() error { return nil }
+
+func (File) Get() string { return "" }
+
+var _ io.ReadCloser = (*File)(nil)
+`,
+	})
+}
+
+func TestFinder_ReverseLookup(t *testing.T) {
+	t.Parallel()
+
+	root := reverseModule(t)
+
+	type found struct {
+		value, pointer    bool
+		interfaceTypeArgs []string
+	}
+
+	local := map[string]found{
+		"example.com/app/app.Reader":     {value: true, pointer: true},
+		"example.com/app/app.ReadCloser": {pointer: true},
+		"example.com/app/app.Getter": {
+			value: true, pointer: true, interfaceTypeArgs: []string{"string"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		imported bool
+		expected map[string]found
+	}{
+		{
+			name:     "module only",
+			expected: local,
+		},
+		{
+			name:     "with imported packages",
+			imported: true,
+			expected: func() map[string]found {
+				expected := map[string]found{
+					"io.Reader":     {value: true, pointer: true},
+					"io.Closer":     {pointer: true},
+					"io.ReadCloser": {pointer: true},
+				}
+				for name, match := range local {
+					expected[name] = match
+				}
+
+				return expected
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, "")
+			require.NoError(t, finder.loadSubject(typeSpec{ImportPath: "example.com/app/store", Name: "File"}))
+			require.NoError(t, finder.scanDirectory(root))
+
+			if tc.imported {
+				finder.scanImportedInterfaces()
+			}
+
+			results := make(map[string]found)
+			for _, match := range finder.getInterfaceMatches() {
+				results[match.PackagePath+"."+match.Interface] = found{
+					value:             match.ValueImplements,
+					pointer:           match.PointerImplements,
+					interfaceTypeArgs: match.InterfaceTypeArgs,
+				}
+			}
+
+			if tc.imported {
+				// Only the interfaces listed are checked: io and its imports
+				// declare more that File happens to satisfy.
+				for name := range results {
+					if _, ok := tc.expected[name]; !ok {
+						delete(results, name)
+					}
+				}
+			}
+
+			assert.Equal(t, tc.expected, results)
+		})
+	}
+}
+
+func TestFinder_LoadSubject(t *testing.T) {
+	t.Parallel()
+
+	root := reverseModule(t)
+
+	testCases := []struct {
+		name        string
+		spec        typeSpec
+		expectedErr error
+	}{
+		{
+			name: "type",
+			spec: typeSpec{ImportPath: "example.com/app/store", Name: "File"},
+		},
+		{
+			name:        "missing type",
+			spec:        typeSpec{ImportPath: "example.com/app/store", Name: "Missing"},
+			expectedErr: ErrTypeNotFound,
+		},
+		{
+			name:        "not a type",
+			spec:        typeSpec{ImportPath: "example.com/app/store", Name: "_"},
+			expectedErr: ErrTypeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, "")
+
+			err := finder.loadSubject(tc.spec)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.spec.Name, finder.subject.Obj().Name())
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// externalTestSuffix ends the package name of an external test package.
+const externalTestSuffix = "_test"
+
+// testPackages holds the test packages of a directory once checked: the
+// test variant of the package, nil without in-package test files, and the
+// external test package, nil without one.
+type testPackages struct {
+	variant  *types.Package
+	external *types.Package
+}
+
+// analyzeTestPackages looks for implementations in the directory's test
+// files the way go test builds them: the in-package test files are checked
+// together with the package's other files into a test variant of the
+// package, and an external foo_test package is checked against that variant.
+// Types already reported from the non-test files are skipped.
+func (f *Finder) analyzeTestPackages(
+	dirPath, importPath string, pkg *types.Package, reported map[string]bool,
+) {
+	tests, ok := f.testPackages[dirPath]
+	if !ok {
+		tests = f.checkTestPackages(dirPath, importPath, pkg)
+		f.testPackages[dirPath] = tests
+	}
+
+	variant := pkg
+
+	if tests.variant != nil {
+		variant = tests.variant
+		f.collectTestResults(dirPath, variant, variant, reported)
+	}
+
+	if tests.external != nil {
+		f.collectTestResults(dirPath, tests.external, variant, nil)
+	}
+}
+
+// checkTestPackages type-checks the test packages of a directory. They are
+// cached per directory, since nothing imports them.
+func (f *Finder) checkTestPackages(
+	dirPath, importPath string, pkg *types.Package,
+) testPackages {
+	var tests testPackages
+
+	internal, external, err := f.parseTestFiles(dirPath)
+	if err != nil {
+		slog.Debug("failed to read test files", "dir", dirPath, "err", err)
+
+		return tests
+	}
+
+	variant := pkg
+
+	if len(internal) > 0 {
+		variant, err = f.checkTestVariant(dirPath, importPath, internal)
+		if err != nil {
+			slog.Debug("failed to type-check tests", "dir", dirPath, "err", err)
+
+			return tests
+		}
+
+		tests.variant = variant
+	}
+
+	if len(external) == 0 {
+		return tests
+	}
+
+	config := *f.config
+	if variant != nil {
+		config.Importer = &testVariantImporter{finder: f, path: importPath, variant: variant}
+	}
+
+	tests.external, err = f.typeCheckPackageWith(&config, importPath+externalTestSuffix, external)
+	if err != nil {
+		slog.Debug("failed to type-check external tests", "dir", dirPath, "err", err)
+	}
+
+	return tests
+}
+
+// checkTestVariant type-checks the package's files together with its
+// in-package test files. The variant is never cached as an import: other
+// packages import the package without its tests.
+func (f *Finder) checkTestVariant(
+	dirPath, importPath string, testFiles []*ast.File,
+) (*types.Package, error) {
+	files, err := f.parsePackageFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.typeCheckPackage(importPath, append(files, testFiles...))
+}
+
+// collectTestResults scans pkg like any other package and tags what it finds
+// as coming from tests. When the target interface is declared in the package
+// under test, it is looked up again in the test variant, whose types are
+// distinct from those of the package without tests.
+func (f *Finder) collectTestResults(
+	dirPath string, pkg, variant *types.Package, skip map[string]bool,
+) {
+	if f.subject != nil {
+		f.findInterfacesInTypedPackage(pkg)
+
+		return
+	}
+
+	restore, err := f.useTargetFrom(variant)
+	if err != nil {
+		slog.Debug("failed to use interface from test variant", "err", err)
+
+		return
+	}
+	defer restore()
+
+	start := len(f.results)
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if skip[name] {
+			continue
+		}
+
+		f.processTypeInScope(scope.Lookup(name), dirPath, pkg)
+	}
+
+	f.collectAssertions(pkg)
+
+	for i := start; i < len(f.results); i++ {
+		f.results[i].InTest = true
+		f.results[i].TestPackage = pkg.Name()
+	}
+}
+
+// useTargetFrom switches the target to its counterpart in variant, if the
+// target is declared in variant's package, and returns a function restoring
+// the original target.
+func (f *Finder) useTargetFrom(variant *types.Package) (func(), error) {
+	target, iface := f.target, f.iface
+	ifaceGeneric, ifaceTypeArgs := f.ifaceGeneric, f.ifaceTypeArgs
+
+	restore := func() {
+		f.target, f.iface = target, iface
+		f.ifaceGeneric, f.ifaceTypeArgs = ifaceGeneric, ifaceTypeArgs
+	}
+
+	if variant == nil || f.target == nil || f.target.Obj().Pkg() == nil ||
+		f.target.Obj().Pkg() == variant ||
+		f.target.Obj().Pkg().Path() != variant.Path() {
+		return restore, nil
+	}
+
+	typeName, ok := variant.Scope().Lookup(f.target.Obj().Name()).(*types.TypeName)
+	if !ok {
+		return restore, nil
+	}
+
+	named, ok := typeName.Type().(*types.Named)
+	if !ok {
+		return restore, nil
+	}
+
+	if err := f.setTarget(named); err != nil {
+		restore()
+
+		return nil, err
+	}
+
+	return restore, nil
+}
+
+// testVariantImporter resolves the package under test to its test variant
+// and every other import through the finder.
+type testVariantImporter struct {
+	finder  *Finder
+	path    string
+	variant *types.Package
+}
+
+func (imp *testVariantImporter) Import(path string) (*types.Package, error) {
+	return imp.ImportFrom(path, "", 0)
+}
+
+func (imp *testVariantImporter) ImportFrom(
+	path, srcDir string, mode types.ImportMode,
+) (*types.Package, error) {
+	if path == imp.path {
+		return imp.variant, nil
+	}
+
+	return imp.finder.ImportFrom(path, srcDir, mode)
+}
+
+// parseTestFiles parses the directory's _test.go files that match the build
+// context and splits them by package clause into in-package and external
+// test files.
+func (f *Finder) parseTestFiles(dirPath string) ([]*ast.File, []*ast.File, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"failed to read directory: %w",
+			err,
+		)
+	}
+
+	var internal, external []*ast.File
+
+	buildContext := f.moduleBuildContext()
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		match, err := buildContext.MatchFile(dirPath, entry.Name())
+		if err != nil || !match {
+			continue
+		}
+
+		file, err := parser.ParseFile(
+			f.fset,
+			filepath.Join(dirPath, entry.Name()),
+			nil,
+			parser.SkipObjectResolution,
+		)
+		if err != nil {
+			continue
+		}
+
+		if strings.HasSuffix(file.Name.Name, externalTestSuffix) {
+			external = append(external, file)
+		} else {
+			internal = append(internal, file)
+		}
+	}
+
+	return internal, external, nil
+}
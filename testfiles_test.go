@@ -0,0 +1,136 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFilesModule(t *testing.T) string {
+	t.Helper()
+
+	return newTempModule(t, map[string]string{
+		"app/app.go": `package app
+
+type Item struct{ Key string }
+
+type Store interface {
+	Get(key string) (Item, error)
+}
+`,
+		"app/fake_test.go": `package app
+
+type fakeStore struct{}
+
+func (fakeStore) Get(key string) (Item, error) { return Item{}, nil }
+`,
+		"app/app_ext_test.go": `package app_test
+
+import "example.com/app/app"
+
+type extStore struct{}
+
+func (*extStore) Get(key string) (app.Item, error) { return app.Item{}, nil }
+
+var _ app.Store = (*extStore)(nil)
+`,
+		"impl/impl.go": `package impl
+
+import "example.com/app/app"
+
+type realStore struct{}
+
+func (realStore) Get(key string) (app.Item, error) { return app.Item{}, nil }
+
+type partial struct{}
+`,
+		"impl/impl_test.go": `package impl
+
+import "example.com/app/app"
+
+type mockStore struct{}
+
+func (mockStore) Get(key string) (app.Item, error) { return app.Item{}, nil }
+
+func (partial) Get(key string) (app.Item, error) { return app.Item{}, nil }
+`,
+		"testonly/only_test.go": `package testonly
+
+import "example.com/app/app"
+
+type onlyStore struct{}
+
+func (onlyStore) Get(key string) (app.Item, error) { return app.Item{}, nil }
+`,
+	})
+}
+
+func TestFinder_TestFiles(t *testing.T) {
+	t.Parallel()
+
+	root := testFilesModule(t)
+
+	type found struct {
+		inTest      bool
+		testPackage string
+	}
+
+	testCases := []struct {
+		name     string
+		tests    bool
+		expected map[string]found
+	}{
+		{
+			name:     "without tests",
+			expected: map[string]found{"realStore": {}},
+		},
+		{
+			name:  "with tests",
+			tests: true,
+			expected: map[string]found{
+				"realStore": {},
+				"fakeStore": {inTest: true, testPackage: "app"},
+				"extStore":  {inTest: true, testPackage: "app_test"},
+				"mockStore": {inTest: true, testPackage: "impl"},
+				"partial":   {inTest: true, testPackage: "impl"},
+				"onlyStore": {inTest: true, testPackage: "testonly"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finder := newModuleFinder(t, root, "Store")
+			finder.tests = tc.tests
+			require.NoError(t, finder.loadInterface(interfaceSpec{ImportPath: "example.com/app/app", Name: "Store"}))
+			require.NoError(t, finder.scanDirectory(root))
+
+			results := make(map[string]found)
+			for _, result := range finder.getResults() {
+				results[result.Struct] = found{inTest: result.InTest, testPackage: result.TestPackage}
+			}
+
+			assert.Equal(t, tc.expected, results)
+		})
+	}
+}
+
+func TestFinder_ParseTestFiles(t *testing.T) {
+	t.Parallel()
+
+	root := testFilesModule(t)
+	finder := newModuleFinder(t, root, "Store")
+
+	internal, external, err := finder.parseTestFiles(root + "/app")
+	require.NoError(t, err)
+	require.Len(t, internal, 1)
+	require.Len(t, external, 1)
+	assert.Equal(t, "app", internal[0].Name.Name)
+	assert.Equal(t, "app_test", external[0].Name.Name)
+
+	_, _, err = finder.parseTestFiles(root + "/missing")
+	require.Error(t, err)
+}
@@ -5,11 +5,11 @@ import (
 	"os"
 )
 
-func validateArgs(
-	interfaceFile, _ string, searchDir string,
-) error {
-	if _, err := os.Stat(interfaceFile); os.IsNotExist(err) {
-		return fmt.Errorf("%w: %s", ErrInterfaceFileNotExist, interfaceFile)
+func validateArgs(spec interfaceSpec, searchDir string) error {
+	if spec.File != "" {
+		if _, err := os.Stat(spec.File); os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrInterfaceFileNotExist, spec.File)
+		}
 	}
 
 	if _, err := os.Stat(searchDir); os.IsNotExist(err) {
@@ -18,3 +18,100 @@ func validateArgs(
 
 	return nil
 }
+
+// validatePatterns rejects package patterns given along with an explicit
+// -dir, since only one of them picks what is searched.
+func validatePatterns(patterns []string, dirSet bool) error {
+	if len(patterns) > 0 && dirSet {
+		return ErrDirWithPatterns
+	}
+
+	return nil
+}
+
+// validateInterfaces rejects the options that work on a single interface
+// when several are searched for.
+func validateInterfaces(opts runOptions) error {
+	if opts.explain.Name != "" || opts.hierarchy || opts.emitAssertions != "" {
+		return ErrInterfacesConflict
+	}
+
+	return nil
+}
+
+func validateNearMiss(nearMiss int) error {
+	if nearMiss < 0 {
+		return fmt.Errorf("%w: %d", ErrNegativeNearMiss, nearMiss)
+	}
+
+	return nil
+}
+
+func validateFormat(format string) error {
+	switch format {
+	case "", formatJSON, formatText:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+func validateBuildTarget(opts runOptions) error {
+	if len(opts.platforms) > 0 && (opts.target.GOOS != "" || opts.target.GOARCH != "") {
+		return ErrPlatformsWithTarget
+	}
+
+	if len(opts.platforms) > 0 && opts.explain.Name != "" {
+		return ErrPlatformsWithExplain
+	}
+
+	return nil
+}
+
+// validateHierarchy rejects the options -hierarchy has no use for: it only
+// looks at interfaces, and only at whole method sets.
+func validateHierarchy(opts runOptions) error {
+	if !opts.hierarchy {
+		return nil
+	}
+
+	if opts.explain.Name != "" || opts.nearMiss > 0 || len(opts.kinds) > 0 ||
+		len(opts.platforms) > 0 || opts.valueOnly {
+		return ErrHierarchyConflict
+	}
+
+	return nil
+}
+
+// validateReverse rejects the options that only apply to implementations of
+// an interface when -type looks for the interfaces a type implements.
+func validateReverse(opts runOptions) error {
+	if len(opts.platforms) > 0 {
+		return ErrPlatformsWithType
+	}
+
+	if opts.explain.Name != "" || opts.hierarchy || opts.assertions ||
+		opts.requireAssertions || opts.emitAssertions != "" || opts.nearMiss > 0 ||
+		len(opts.kinds) > 0 || opts.valueOnly || opts.format != "" {
+		return ErrReverseConflict
+	}
+
+	return nil
+}
+
+func validateEmitAssertions(opts runOptions) error {
+	switch opts.emitAssertions {
+	case "":
+		return nil
+	case emitWrite, emitDiff:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownEmitMode, opts.emitAssertions)
+	}
+
+	if opts.explain.Name != "" || opts.hierarchy || len(opts.platforms) > 0 ||
+		opts.deps || opts.std {
+		return ErrEmitConflict
+	}
+
+	return nil
+}
@@ -20,9 +20,15 @@ func TestValidateArgs(t *testing.T) {
 	testCases := []struct {
 		name          string
 		interfaceFile string
+		importPath    string
 		searchDir     string
 		expectError   bool
 	}{
+		{
+			name:       "import path spec skips file check",
+			importPath: "io",
+			searchDir:  tempDir,
+		},
 		{
 			name:          "valid args",
 			interfaceFile: tempFile,
@@ -47,7 +53,13 @@ func TestValidateArgs(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			err := validateArgs(tc.interfaceFile, "TestInterface", tc.searchDir)
+			spec := interfaceSpec{
+				File:       tc.interfaceFile,
+				ImportPath: tc.importPath,
+				Name:       "TestInterface",
+			}
+
+			err := validateArgs(spec, tc.searchDir)
 
 			if tc.expectError {
 				require.Error(t, err)
@@ -57,3 +69,181 @@ func TestValidateArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateNearMiss(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateNearMiss(0))
+	require.NoError(t, validateNearMiss(2))
+	require.ErrorIs(t, validateNearMiss(-1), ErrNegativeNearMiss)
+}
+
+func TestValidateFormat(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateFormat(""))
+	require.NoError(t, validateFormat("json"))
+	require.NoError(t, validateFormat("text"))
+	require.ErrorIs(t, validateFormat("yaml"), ErrUnknownFormat)
+}
+
+func TestValidateBuildTarget(t *testing.T) {
+	t.Parallel()
+
+	platforms := []platform{{GOOS: "linux", GOARCH: "amd64"}}
+
+	require.NoError(t, validateBuildTarget(runOptions{}))
+	require.NoError(t, validateBuildTarget(runOptions{target: platform{GOOS: "linux"}}))
+	require.NoError(t, validateBuildTarget(runOptions{platforms: platforms}))
+	require.ErrorIs(t,
+		validateBuildTarget(runOptions{platforms: platforms, target: platform{GOARCH: "arm64"}}),
+		ErrPlatformsWithTarget)
+	require.ErrorIs(t,
+		validateBuildTarget(runOptions{platforms: platforms, explain: typeSpec{ImportPath: "io", Name: "Writer"}}),
+		ErrPlatformsWithExplain)
+}
+
+func TestValidateHierarchy(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateHierarchy(runOptions{}))
+	require.NoError(t, validateHierarchy(runOptions{hierarchy: true, tests: true, format: formatText}))
+	require.NoError(t, validateHierarchy(runOptions{nearMiss: 2}))
+	require.ErrorIs(t, validateHierarchy(runOptions{hierarchy: true, nearMiss: 2}), ErrHierarchyConflict)
+	require.ErrorIs(t,
+		validateHierarchy(runOptions{hierarchy: true, kinds: map[string]bool{kindStruct: true}}),
+		ErrHierarchyConflict)
+	require.ErrorIs(t,
+		validateHierarchy(runOptions{hierarchy: true, explain: typeSpec{ImportPath: "io", Name: "Writer"}}),
+		ErrHierarchyConflict)
+}
+
+func TestValidateEmitAssertions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		opts        runOptions
+		expectedErr error
+	}{
+		{name: "off", opts: runOptions{}},
+		{name: "write", opts: runOptions{emitAssertions: emitWrite}},
+		{name: "diff", opts: runOptions{emitAssertions: emitDiff, tests: true}},
+		{name: "unknown mode", opts: runOptions{emitAssertions: "patch"}, expectedErr: ErrUnknownEmitMode},
+		{
+			name:        "with hierarchy",
+			opts:        runOptions{emitAssertions: emitDiff, hierarchy: true},
+			expectedErr: ErrEmitConflict,
+		},
+		{
+			name: "with platforms",
+			opts: runOptions{
+				emitAssertions: emitWrite,
+				platforms:      []platform{{GOOS: "linux", GOARCH: "amd64"}},
+			},
+			expectedErr: ErrEmitConflict,
+		},
+		{
+			name:        "with deps",
+			opts:        runOptions{emitAssertions: emitWrite, deps: true},
+			expectedErr: ErrEmitConflict,
+		},
+		{
+			name:        "with std",
+			opts:        runOptions{emitAssertions: emitDiff, std: true},
+			expectedErr: ErrEmitConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateEmitAssertions(tc.opts)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateReverse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		opts        runOptions
+		expectedErr error
+	}{
+		{name: "plain", opts: runOptions{}},
+		{name: "with scan options", opts: runOptions{tests: true, imported: true, deps: true}},
+		{
+			name:        "with platforms",
+			opts:        runOptions{platforms: []platform{{GOOS: "linux", GOARCH: "amd64"}}},
+			expectedErr: ErrPlatformsWithType,
+		},
+		{
+			name:        "with explain",
+			opts:        runOptions{explain: typeSpec{ImportPath: "./store", Name: "File"}},
+			expectedErr: ErrReverseConflict,
+		},
+		{name: "with hierarchy", opts: runOptions{hierarchy: true}, expectedErr: ErrReverseConflict},
+		{name: "with assertions", opts: runOptions{assertions: true}, expectedErr: ErrReverseConflict},
+		{
+			name:        "with require assertions",
+			opts:        runOptions{requireAssertions: true},
+			expectedErr: ErrReverseConflict,
+		},
+		{
+			name:        "with emit assertions",
+			opts:        runOptions{emitAssertions: emitDiff},
+			expectedErr: ErrReverseConflict,
+		},
+		{name: "with near miss", opts: runOptions{nearMiss: 1}, expectedErr: ErrReverseConflict},
+		{
+			name:        "with kinds",
+			opts:        runOptions{kinds: map[string]bool{kindStruct: true}},
+			expectedErr: ErrReverseConflict,
+		},
+		{name: "with value only", opts: runOptions{valueOnly: true}, expectedErr: ErrReverseConflict},
+		{name: "with format", opts: runOptions{format: formatJSON}, expectedErr: ErrReverseConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateReverse(tc.opts)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validatePatterns(nil, false))
+	require.NoError(t, validatePatterns(nil, true))
+	require.NoError(t, validatePatterns([]string{"./..."}, false))
+	require.ErrorIs(t, validatePatterns([]string{"./..."}, true), ErrDirWithPatterns)
+}
+
+func TestValidateInterfaces(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateInterfaces(runOptions{}))
+	require.NoError(t, validateInterfaces(runOptions{nearMiss: 1, assertions: true}))
+	require.ErrorIs(t, validateInterfaces(runOptions{explain: typeSpec{Name: "T"}}), ErrInterfacesConflict)
+	require.ErrorIs(t, validateInterfaces(runOptions{hierarchy: true}), ErrInterfacesConflict)
+	require.ErrorIs(t, validateInterfaces(runOptions{emitAssertions: emitDiff}), ErrInterfacesConflict)
+}
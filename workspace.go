@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// workspaceModule is one module of the scan: the module being scanned, or
+// one of the modules a go.work file uses.
+type workspaceModule struct {
+	Path  string
+	Root  string
+	GoMod *goModFile
+}
+
+// findWorkFile locates the go.work that applies in dir the way the go
+// command does: $GOWORK when set, where "off" disables workspaces, otherwise
+// the nearest go.work walking up from dir.
+func findWorkFile(dir string) string {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return ""
+	case "":
+	default:
+		if abs, err := filepath.Abs(gowork); err == nil {
+			return abs
+		}
+
+		return gowork
+	}
+
+	dir = filepath.Clean(dir)
+
+	for {
+		if isFile(filepath.Join(dir, "go.work")) {
+			return filepath.Join(dir, "go.work")
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// loadWorkspace reads the go.work file and the go.mod of every module it
+// uses. The module holding the working directory, or else the first one,
+// becomes the finder's main module.
+func (f *Finder) loadWorkspace() error {
+	content, err := os.ReadFile(f.workFile)
+	if err != nil {
+		return fmt.Errorf("failed to read go.work: %w", err)
+	}
+
+	work := parseGoWork(string(content))
+	workRoot := filepath.Dir(f.workFile)
+
+	for _, use := range work.Uses {
+		root := filepath.FromSlash(use)
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(workRoot, root)
+		}
+
+		content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err != nil {
+			return fmt.Errorf("failed to read go.mod of workspace module %s: %w", use, err)
+		}
+
+		goMod, err := parseGoMod(string(content))
+		if err != nil {
+			return fmt.Errorf("workspace module %s: %w", use, err)
+		}
+
+		f.modules = append(f.modules, workspaceModule{
+			Path:  goMod.Module,
+			Root:  root,
+			GoMod: goMod,
+		})
+	}
+
+	if len(f.modules) == 0 {
+		return fmt.Errorf("%w: %s", ErrNoWorkspaceModules, f.workFile)
+	}
+
+	f.workReplaces = work.Replaces
+
+	main := f.modules[0]
+	if module := f.moduleForDir("."); module != nil {
+		main = *module
+	}
+
+	f.goMod = main.GoMod
+	f.modulePath = main.Path
+	f.moduleRoot = main.Root
+
+	return nil
+}
+
+// workspaceModules returns the modules of the scan. A finder set up without
+// a go.work has just its main module.
+func (f *Finder) workspaceModules() []workspaceModule {
+	if len(f.modules) > 0 {
+		return f.modules
+	}
+
+	if f.modulePath == "" {
+		return nil
+	}
+
+	return []workspaceModule{{Path: f.modulePath, Root: f.moduleRoot, GoMod: f.goMod}}
+}
+
+// moduleForDir returns the module whose tree holds dir; with nested module
+// roots, the innermost one.
+func (f *Finder) moduleForDir(dir string) *workspaceModule {
+	var (
+		found     *workspaceModule
+		foundRoot string
+	)
+
+	modules := f.workspaceModules()
+	for i := range modules {
+		root, err := filepath.Abs(modules[i].Root)
+		if err != nil || !isWithin(root, dir) {
+			continue
+		}
+
+		if found == nil || len(root) > len(foundRoot) {
+			found, foundRoot = &modules[i], root
+		}
+	}
+
+	return found
+}
+
+// moduleForImport returns the module providing importPath: the one with the
+// longest module path that prefixes it.
+func (f *Finder) moduleForImport(importPath string) *workspaceModule {
+	var found *workspaceModule
+
+	modules := f.workspaceModules()
+	for i := range modules {
+		if !hasPathPrefix(importPath, modules[i].Path) {
+			continue
+		}
+
+		if found == nil || len(modules[i].Path) > len(found.Path) {
+			found = &modules[i]
+		}
+	}
+
+	return found
+}
+
+// modulePathForDir is the path of the module holding dir, a dependency's
+// included, empty outside all of them.
+func (f *Finder) modulePathForDir(dir string) string {
+	if dep, ok := f.dependencyForDir(dir); ok {
+		return dep.Module
+	}
+
+	if module := f.moduleForDir(dir); module != nil {
+		return module.Path
+	}
+
+	return ""
+}
+
+// searchRoots lists the directories a scan of searchDir walks. Scanning the
+// root of a workspace covers all of its modules, including those used from
+// outside the workspace directory.
+func (f *Finder) searchRoots(searchDir string) []string {
+	roots := []string{searchDir}
+
+	if f.workFile == "" {
+		return roots
+	}
+
+	workRoot := filepath.Dir(f.workFile)
+
+	absSearch, err := filepath.Abs(searchDir)
+	if err != nil || absSearch != workRoot {
+		return roots
+	}
+
+	for _, module := range f.modules {
+		if !isWithin(workRoot, module.Root) {
+			roots = append(roots, module.Root)
+		}
+	}
+
+	return roots
+}
+
+// isNestedModule reports whether dir holds a go.mod of a module that is not
+// part of the scan yet: a module nested inside a scanned one, or one outside
+// them all.
+func (f *Finder) isNestedModule(dir string) bool {
+	if !isFile(filepath.Join(dir, "go.mod")) {
+		return false
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	module := f.moduleForDir(absDir)
+	if module == nil {
+		return true
+	}
+
+	root, err := filepath.Abs(module.Root)
+
+	return err == nil && root != absDir
+}
+
+// isNestedModuleRoot reports whether dir is the root of a nested module,
+// whether or not it is part of the scan yet.
+func (f *Finder) isNestedModuleRoot(dir string) bool {
+	if f.isNestedModule(dir) {
+		return true
+	}
+
+	absDir, err := filepath.Abs(dir)
+
+	return err == nil && f.nestedRoots[absDir]
+}
+
+// useEnclosingModule adds the nested module holding dir, if any, to the
+// scan, so the packages in dir get import paths from its own go.mod.
+func (f *Finder) useEnclosingModule(dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	if root, ok := findModuleRoot(absDir); ok && f.isNestedModule(root) {
+		return f.addNestedModule(root)
+	}
+
+	return nil
+}
+
+// addNestedModule adds the module rooted at dir to the scan, so its packages
+// get import paths from its own go.mod and its imports resolve against its
+// own requirements.
+func (f *Finder) addNestedModule(dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve nested module %s: %w", dir, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return fmt.Errorf("failed to read go.mod of nested module %s: %w", dir, err)
+	}
+
+	goMod, err := parseGoMod(string(content))
+	if err != nil {
+		return fmt.Errorf("nested module %s: %w", dir, err)
+	}
+
+	slog.Debug("adding nested module", "dir", root, "module", goMod.Module)
+
+	f.modules = append(f.workspaceModules(), workspaceModule{
+		Path:  goMod.Module,
+		Root:  root,
+		GoMod: goMod,
+	})
+
+	if f.nestedRoots == nil {
+		f.nestedRoots = make(map[string]bool)
+	}
+
+	f.nestedRoots[root] = true
+
+	return nil
+}
+
+// vendorRoot is where vendored dependencies live: next to go.work in a
+// workspace, otherwise in the module root.
+func (f *Finder) vendorRoot() string {
+	if f.workFile != "" {
+		return filepath.Join(filepath.Dir(f.workFile), "vendor")
+	}
+
+	return filepath.Join(f.moduleRoot, "vendor")
+}
+
+// requirementOrder lists the modules whose requirements resolve an import
+// from srcDir: the importing module first, so its own required version wins.
+func (f *Finder) requirementOrder(srcDir string) []workspaceModule {
+	modules := f.workspaceModules()
+
+	importer := f.moduleForDir(srcDir)
+	if srcDir == "" || importer == nil {
+		return modules
+	}
+
+	ordered := []workspaceModule{*importer}
+
+	for _, module := range modules {
+		if module.Root != importer.Root {
+			ordered = append(ordered, module)
+		}
+	}
+
+	return ordered
+}
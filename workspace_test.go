@@ -0,0 +1,178 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindWorkFile(t *testing.T) {
+	// not parallel: sets GOWORK with t.Setenv
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.work":        "go 1.24\n\nuse ./app\n",
+		"app/go.mod":     "module example.com/app\n\ngo 1.24\n",
+		"app/pkg/pkg.go": "package pkg\n",
+	})
+
+	custom := filepath.Join(root, "custom.work")
+
+	testCases := []struct {
+		name   string
+		gowork string
+		want   string
+	}{
+		{
+			name: "nearest go.work walking up",
+			want: filepath.Join(root, "go.work"),
+		},
+		{
+			name:   "GOWORK off disables workspaces",
+			gowork: "off",
+		},
+		{
+			name:   "GOWORK names the file",
+			gowork: custom,
+			want:   custom,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("GOWORK", tc.gowork)
+
+			assert.Equal(t, tc.want, findWorkFile(filepath.Join(root, "app", "pkg")))
+		})
+	}
+}
+
+func TestFinder_Workspace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	workRoot := filepath.Join(dir, "work")
+	writeTree(t, dir, map[string]string{
+		"work/go.work":    "go 1.24\n\nuse (\n\t./api\n\t../svc\n)\n",
+		"work/api/go.mod": "module example.com/api\n\ngo 1.24\n",
+		"work/api/api.go": `package api
+
+type Server interface{ Start() error }
+`,
+		"work/api/local/local.go": `package local
+
+type Local struct{}
+
+func (Local) Start() error { return nil }
+`,
+		"work/tools/tools.go": `package tools
+
+type Tool struct{}
+
+func (Tool) Start() error { return nil }
+`,
+		"svc/go.mod": "module example.com/svc\n\ngo 1.24\n\nrequire example.com/api v0.0.0\n",
+		"svc/web/web.go": `package web
+
+import "example.com/api"
+
+type Web struct{}
+
+func (*Web) Start() error { return nil }
+
+var _ api.Server = (*Web)(nil)
+`,
+	})
+
+	finder := NewFinder("Server")
+	finder.workFile = filepath.Join(workRoot, "go.work")
+	require.NoError(t, finder.loadWorkspace())
+
+	assert.Equal(t, "example.com/api", finder.modulePath)
+	require.NoError(t, finder.loadInterface(interfaceSpec{
+		File: filepath.Join(workRoot, "api", "api.go"),
+		Name: "Server",
+	}))
+	require.NoError(t, finder.scanDirectory(workRoot))
+
+	results := finder.getResults()
+	require.Len(t, results, 2)
+
+	modules := make(map[string]string, len(results))
+	for _, impl := range results {
+		modules[impl.PackagePath+"."+impl.Struct] = impl.Module
+	}
+
+	assert.Equal(t, map[string]string{
+		"example.com/api/local.Local": "example.com/api",
+		"example.com/svc/web.Web":     "example.com/svc",
+	}, modules)
+}
+
+func TestFinder_WorkspaceWithoutModules(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"go.work": "go 1.24\n"})
+
+	finder := NewFinder("Server")
+	finder.workFile = filepath.Join(root, "go.work")
+	require.ErrorIs(t, finder.loadWorkspace(), ErrNoWorkspaceModules)
+}
+
+func TestFinder_ModuleForDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	finder := NewFinder("Server")
+	finder.modules = []workspaceModule{
+		{Path: "example.com/outer", Root: root},
+		{Path: "example.com/outer/inner", Root: filepath.Join(root, "inner")},
+	}
+
+	testCases := []struct {
+		name       string
+		dir        string
+		importPath string
+		want       string
+	}{
+		{
+			name:       "outer module",
+			dir:        filepath.Join(root, "pkg"),
+			importPath: "example.com/outer/pkg",
+			want:       "example.com/outer",
+		},
+		{
+			name:       "innermost module wins",
+			dir:        filepath.Join(root, "inner", "pkg"),
+			importPath: "example.com/outer/inner/pkg",
+			want:       "example.com/outer/inner",
+		},
+		{
+			name:       "outside every module",
+			dir:        os.TempDir(),
+			importPath: "example.com/other",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, finder.modulePathForDir(tc.dir))
+
+			module := finder.moduleForImport(tc.importPath)
+			if tc.want == "" {
+				assert.Nil(t, module)
+
+				return
+			}
+
+			require.NotNil(t, module)
+			assert.Equal(t, tc.want, module.Path)
+		})
+	}
+}